@@ -1,6 +1,9 @@
 package dbmodels
 
 import (
+	"fmt"
+	"strings"
+
 	"gorm.io/gorm"
 	"bibleapp.server/internal/models"
 )
@@ -30,11 +33,58 @@ type KeyGenreEnglish struct {
 	models.KeyGenreEnglish
 }
 
+type Bible struct {
+	gorm.Model
+	models.Bible
+	Books			[]Book
+}
+
+type Book struct {
+	gorm.Model
+	models.Book
+	Chapters		[]Chapter
+}
+
+type Chapter struct {
+	gorm.Model
+	models.Chapter
+	Verses			[]Verse
+}
+
 type Verse struct {
 	gorm.Model
+	VID				uint `gorm:"index"` //BBCCCVVV, as in t_* tables and cross references
 	Number			uint
+	EndNumber		uint //Last verse of a bridge, e.g. 1-2
 	Text			string
+	Paragraph		string //USFM marker of the paragraph the verse starts
+	Hash			string //Of the verse as last imported, markup included
+	ChapterID		uint
 	Words			[]Word `gorm:"many2many:verse_words;"`
+	Headings		[]Heading
+	Notes			[]Note
+	Spans			[]Span
+	Tokens			[]InterlinearToken
+}
+
+type Heading struct {
+	gorm.Model
+	models.Heading
+}
+
+type Note struct {
+	gorm.Model
+	models.Note
+}
+
+type Span struct {
+	gorm.Model
+	models.Span
+}
+
+type InterlinearToken struct {
+	gorm.Model
+	models.InterlinearToken
 }
 
 type Word struct {
@@ -47,25 +97,85 @@ type VerseWord struct {
 	models.VerseWord
 }
 
-func SetupDB(db *gorm.DB) (ok bool) {
-	err := db.SetupJoinTable(&Verse{}, "Words", &VerseWord{})
-	if err != nil {
-		return false
-	}
-	db.AutoMigrate(
-		&BibleVerseKey{}, 
+type Lexicon struct {
+	gorm.Model
+	models.Lexicon
+	Entries			[]LexiconEntry
+}
+
+type LexiconEntry struct {
+	gorm.Model
+	models.LexiconEntry
+}
+
+type VerseWordEntry struct {
+	gorm.Model
+	models.VerseWordEntry
+}
+
+// tables are the models SetupDB migrates.
+func tables() []interface{} {
+	return []interface{}{
+		&BibleVerseKey{},
 		&CrossReference{},
 		&KeyEnglish{},
 		&KeyAbbreviationsEnglish{},
 		&KeyGenreEnglish{},
+		&Bible{},
+		&Book{},
+		&Chapter{},
 		&Word{},
 		&Verse{},
-	)
+		&Heading{},
+		&Note{},
+		&Span{},
+		&InterlinearToken{},
+		&Lexicon{},
+		&LexiconEntry{},
+		&VerseWordEntry{},
+	}
+}
+
+func SetupDB(db *gorm.DB) (ok bool) {
+	err := db.SetupJoinTable(&Verse{}, "Words", &VerseWord{})
+	if err != nil {
+		return false
+	}
+	db.AutoMigrate(tables()...)
+	// Words used to be linked to lexicon entries as a whole, the links are
+	// now kept for every verse word (VerseWordEntry)
+	db.Migrator().DropTable("word_entries")
 
 	return true
 
 }
 
+/**
+ * CheckDB prepares db as SetupDB does but leaves the schema alone, for runs
+ * that must not change the database. It fails when a table is missing, as
+ * in a database SetupDB never ran on.
+ */
+func CheckDB(db *gorm.DB) error {
+	if err := db.SetupJoinTable(&Verse{}, "Words", &VerseWord{}); err != nil {
+		return err
+	}
+	var missing []string
+	for _, model := range append(tables(), &VerseWord{}) {
+		if db.Migrator().HasTable(model) {
+			continue
+		}
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		missing = append(missing, stmt.Schema.Table)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing tables %s, the database needs setting up", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 
 
 
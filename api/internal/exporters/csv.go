@@ -0,0 +1,47 @@
+package exporters
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+
+	"bibleapp.server/internal/sources"
+	bible "bibleapp.server/pkg/bible_parser"
+)
+
+func init() {
+	Register("csv", WriteCSV)
+}
+
+// WriteCSV writes the verses as a CSV file at path, a row per verse with
+// its BBCCCVVV id, book code, chapter, verse, last verse of a bridge and
+// text.
+func WriteCSV(path string, meta sources.Metadata, verses []sources.Verse) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	out := csv.NewWriter(file)
+	out.Write([]string{"id", "book", "chapter", "verse", "end_verse", "text"})
+	for _, v := range verses {
+		endNumber := ""
+		if v.EndNumber > v.Number {
+			endNumber = fmt.Sprint(v.EndNumber)
+		}
+		out.Write([]string{
+			fmt.Sprint(uint(bible.NewVerseID(v.Book, v.Chapter, v.Number))),
+			v.Book.Code(),
+			fmt.Sprint(v.Chapter),
+			fmt.Sprint(v.Number),
+			endNumber,
+			v.Text,
+		})
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,168 @@
+// Package exporters writes the verses of a stored version to files.
+package exporters
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"bibleapp.server/internal/sources"
+)
+
+/**
+ * WriteFunc writes the verses of a version to path, which is a file or a
+ * directory depending on the format. verses are in order, as returned by
+ * loader.Read.
+ */
+type WriteFunc func(path string, meta sources.Metadata, verses []sources.Verse) error
+
+var exporters = map[string]WriteFunc{}
+
+// Register makes an exporter available under a format name.
+func Register(format string, write WriteFunc) {
+	exporters[format] = write
+}
+
+// Formats returns the registered format names, sorted.
+func Formats() []string {
+	formats := make([]string, 0, len(exporters))
+	for format := range exporters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Write writes the verses to path in the given format.
+func Write(format string, path string, meta sources.Metadata, verses []sources.Verse) error {
+	write, ok := exporters[strings.ToLower(format)]
+	if !ok {
+		return fmt.Errorf("unknown format %q, expected one of %s", format, strings.Join(Formats(), ", "))
+	}
+	return write(path, meta, verses)
+}
+
+// inline is a character style or tagged word within the text of a verse.
+type inline struct {
+	style      string
+	start, end int
+	word       *sources.Word
+}
+
+// markup receives the text of a verse interleaved with its markup.
+type markup interface {
+	text(s string)
+	open(item inline)
+	close(item inline)
+	note(n sources.Note)
+}
+
+/**
+ * render walks the text of a verse, telling m where character styles and
+ * tagged words open and close and where notes sit. Markup ending at an
+ * offset is closed before the notes there, which come before the markup
+ * opening there. Overlapping styles are closed and opened again so that
+ * the markup always nests.
+ */
+func render(v sources.Verse, m markup) {
+	clamp := func(offset int) int {
+		if offset < 0 {
+			return 0
+		}
+		if offset > len(v.Text) {
+			return len(v.Text)
+		}
+		return offset
+	}
+
+	// Words are written with their attributes, their "\w" spans with them
+	var items []inline
+	for _, span := range v.Spans {
+		if span.Style != "w" && clamp(span.Start) < clamp(span.End) {
+			items = append(items, inline{style: span.Style, start: clamp(span.Start), end: clamp(span.End)})
+		}
+	}
+	for i := range v.Words {
+		w := &v.Words[i]
+		if clamp(w.Start) < clamp(w.End) {
+			items = append(items, inline{style: "w", start: clamp(w.Start), end: clamp(w.End), word: w})
+		}
+	}
+	// Outer markup first
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].start != items[j].start {
+			return items[i].start < items[j].start
+		}
+		if items[i].end != items[j].end {
+			return items[i].end > items[j].end
+		}
+		return items[i].word == nil && items[j].word != nil
+	})
+	notes := append([]sources.Note(nil), v.Notes...)
+	sort.SliceStable(notes, func(i, j int) bool { return notes[i].Offset < notes[j].Offset })
+
+	var open []inline
+	next, written := 0, 0
+	for offset := 0; offset <= len(v.Text); offset++ {
+		closing := false
+		for _, item := range open {
+			closing = closing || item.end == offset
+		}
+		opening := next < len(items) && items[next].start == offset
+		noting := len(notes) > 0 && clamp(notes[0].Offset) == offset
+		if !closing && !opening && !noting && offset < len(v.Text) {
+			continue
+		}
+		if written < offset {
+			m.text(v.Text[written:offset])
+			written = offset
+		}
+
+		if closing {
+			var reopen []inline
+			for len(open) > 0 {
+				item := open[len(open)-1]
+				open = open[:len(open)-1]
+				m.close(item)
+				if item.end != offset {
+					reopen = append(reopen, item)
+				}
+				ended := false
+				for _, o := range open {
+					ended = ended || o.end == offset
+				}
+				if !ended {
+					break
+				}
+			}
+			for i := len(reopen) - 1; i >= 0; i-- {
+				m.open(reopen[i])
+				open = append(open, reopen[i])
+			}
+		}
+		for len(notes) > 0 && clamp(notes[0].Offset) == offset {
+			m.note(notes[0])
+			notes = notes[1:]
+		}
+		for next < len(items) && items[next].start == offset {
+			m.open(items[next])
+			open = append(open, items[next])
+			next++
+		}
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		m.close(open[i])
+	}
+}
+
+// noteCaller returns the caller of a note, or the USFM default for its
+// style.
+func noteCaller(n sources.Note) string {
+	if n.Caller != "" {
+		return n.Caller
+	}
+	if n.Style == "x" {
+		return "-"
+	}
+	return "+"
+}
@@ -0,0 +1,67 @@
+package exporters
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"bibleapp.server/internal/sources"
+	bible "bibleapp.server/pkg/bible_parser"
+)
+
+func init() {
+	Register("json", WriteJSON)
+}
+
+// resultSet is the layout of the scrollmapper bible_databases JSON export,
+// as read by cmd/importer.
+type resultSet struct {
+	ResultSet struct {
+		Row []jsonRow `json:"row"`
+	} `json:"resultset"`
+}
+
+type jsonRow struct {
+	Field []interface{} `json:"field"`
+}
+
+/**
+ * WriteJSON writes the verses into the directory at path the way the
+ * scrollmapper dataset holds them: a t_<abbreviation>.json verse table
+ * (id, b, c, v, t) and a bible_version_key.json naming it, so that
+ * cmd/importer reads the directory back. Only the text of the verses fits.
+ */
+func WriteJSON(path string, meta sources.Metadata, verses []sources.Verse) error {
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return err
+	}
+	table := "t_" + strings.ToLower(meta.Abbreviation)
+
+	var key resultSet
+	key.ResultSet.Row = []jsonRow{{Field: []interface{}{
+		1, table, meta.Abbreviation, meta.Language, meta.Name, meta.Description, "", "", "", "",
+	}}}
+	if err := writeJSONFile(filepath.Join(path, "bible_version_key.json"), key); err != nil {
+		return err
+	}
+
+	var rows resultSet
+	rows.ResultSet.Row = make([]jsonRow, len(verses))
+	for i, v := range verses {
+		id := bible.NewVerseID(v.Book, v.Chapter, v.Number)
+		rows.ResultSet.Row[i].Field = []interface{}{
+			uint(id), int(v.Book) + 1, v.Chapter, v.Number, v.Text,
+		}
+	}
+	return writeJSONFile(filepath.Join(path, table+".json"), rows)
+}
+
+func writeJSONFile(path string, content interface{}) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return json.NewEncoder(file).Encode(content)
+}
@@ -0,0 +1,189 @@
+package exporters
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+
+	"bibleapp.server/internal/sources"
+	bible "bibleapp.server/pkg/bible_parser"
+)
+
+func init() {
+	Register("osis", WriteOSIS)
+}
+
+// USFM character styles and the OSIS elements they are written as.
+var osisElements = map[string]string{
+	"nd":  `<divineName>`,
+	"add": `<transChange type="added">`,
+	"tl":  `<foreign>`,
+	"wj":  `<q who="Jesus" marker="">`,
+	"it":  `<hi type="italic">`,
+	"bd":  `<hi type="bold">`,
+	"sc":  `<hi type="small-caps">`,
+	"sup": `<hi type="super">`,
+	"em":  `<hi type="underline">`,
+}
+
+/**
+ * WriteOSIS writes the verses as an OSIS document at path, with a book
+ * division per book, chapter elements and verse containers. Section
+ * headings become titles (psalm titles for "d"), paragraphs milestones and
+ * poetry lines empty <l> elements.
+ */
+func WriteOSIS(path string, meta sources.Metadata, verses []sources.Verse) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	out := bufio.NewWriter(file)
+
+	work := xmlEscape(meta.Abbreviation)
+	fmt.Fprintln(out, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(out, `<osis xmlns="http://www.bibletechnologies.net/2003/OSIS/namespace" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://www.bibletechnologies.net/2003/OSIS/namespace http://www.bibletechnologies.net/osisCore.2.1.1.xsd">`)
+	fmt.Fprintf(out, "<osisText osisIDWork=\"%s\" osisRefWork=\"Bible\" xml:lang=\"%s\">\n", work, xmlEscape(meta.Language))
+	fmt.Fprintln(out, "<header>")
+	fmt.Fprintf(out, "<work osisWork=\"%s\">\n", work)
+	fmt.Fprintf(out, "<title>%s</title>\n", xmlEscape(meta.Name))
+	if meta.Description != "" {
+		fmt.Fprintf(out, "<description>%s</description>\n", xmlEscape(meta.Description))
+	}
+	fmt.Fprintf(out, "<language>%s</language>\n", xmlEscape(meta.Language))
+	if meta.Versification != "" {
+		fmt.Fprintf(out, "<refSystem>Bible.%s</refSystem>\n", xmlEscape(meta.Versification))
+	}
+	fmt.Fprintln(out, "</work>")
+	fmt.Fprintln(out, "</header>")
+
+	var book bible.Book
+	var chapter uint
+	for i, v := range verses {
+		if i == 0 || v.Book != book {
+			if i > 0 {
+				fmt.Fprintln(out, "</chapter>")
+				fmt.Fprintln(out, "</div>")
+			}
+			book, chapter = v.Book, 0
+			fmt.Fprintf(out, "<div type=\"book\" osisID=\"%s\">\n", book.OSIS())
+		}
+		if v.Chapter != chapter {
+			if chapter != 0 {
+				fmt.Fprintln(out, "</chapter>")
+			}
+			chapter = v.Chapter
+			fmt.Fprintf(out, "<chapter osisID=\"%s.%d\">\n", book.OSIS(), chapter)
+		}
+		writeOSISVerse(out, v)
+	}
+	if len(verses) > 0 {
+		fmt.Fprintln(out, "</chapter>")
+		fmt.Fprintln(out, "</div>")
+	}
+	fmt.Fprintln(out, "</osisText>")
+	fmt.Fprintln(out, "</osis>")
+	return out.Flush()
+}
+
+// writeOSISVerse writes the headings, paragraph and text of a verse.
+func writeOSISVerse(out *bufio.Writer, v sources.Verse) {
+	for _, heading := range v.Headings {
+		if heading.Style == "d" {
+			fmt.Fprintf(out, "<title type=\"psalm\" canonical=\"true\">%s</title>\n", xmlEscape(heading.Text))
+		} else {
+			fmt.Fprintf(out, "<title>%s</title>\n", xmlEscape(heading.Text))
+		}
+	}
+	switch {
+	case strings.HasPrefix(v.Paragraph, "q"):
+		level := strings.TrimLeft(v.Paragraph, "qrcm")
+		if level == "" {
+			level = "1"
+		}
+		fmt.Fprintf(out, "<l level=\"%s\"/>\n", level)
+	case v.Paragraph != "":
+		fmt.Fprintln(out, `<milestone type="x-p" marker="¶"/>`)
+	}
+
+	osisID := fmt.Sprintf("%s.%d.%d", v.Book.OSIS(), v.Chapter, v.Number)
+	for n := v.Number + 1; n <= v.EndNumber; n++ {
+		osisID += fmt.Sprintf(" %s.%d.%d", v.Book.OSIS(), v.Chapter, n)
+	}
+	var text strings.Builder
+	render(v, &osisMarkup{out: &text})
+	fmt.Fprintf(out, "<verse osisID=\"%s\">%s</verse>\n", osisID, text.String())
+}
+
+// osisMarkup writes verse markup as OSIS elements.
+type osisMarkup struct {
+	out *strings.Builder
+}
+
+func (m *osisMarkup) text(s string) {
+	m.out.WriteString(xmlEscape(s))
+}
+
+func (m *osisMarkup) open(item inline) {
+	if w := item.word; w != nil {
+		var lemmas []string
+		for _, strong := range strings.Fields(w.Strong) {
+			lemmas = append(lemmas, "strong:"+strong)
+		}
+		for _, lemma := range strings.Fields(w.Lemma) {
+			lemmas = append(lemmas, "lemma:"+lemma)
+		}
+		m.out.WriteString("<w")
+		if len(lemmas) > 0 {
+			fmt.Fprintf(m.out, " lemma=\"%s\"", xmlEscape(strings.Join(lemmas, " ")))
+		}
+		if w.Morph != "" {
+			fmt.Fprintf(m.out, " morph=\"%s\"", xmlEscape(w.Morph))
+		}
+		m.out.WriteString(">")
+		return
+	}
+	if element, ok := osisElements[item.style]; ok {
+		m.out.WriteString(element)
+		return
+	}
+	fmt.Fprintf(m.out, "<seg type=\"x-%s\">", xmlEscape(item.style))
+}
+
+func (m *osisMarkup) close(item inline) {
+	if item.word != nil {
+		m.out.WriteString("</w>")
+		return
+	}
+	element, ok := osisElements[item.style]
+	if !ok {
+		element = "<seg>"
+	}
+	// The name of the opening element
+	name := strings.Fields(strings.Trim(element, "<>"))[0]
+	m.out.WriteString("</" + name + ">")
+}
+
+func (m *osisMarkup) note(n sources.Note) {
+	noteType := ""
+	if n.Style == "x" {
+		noteType = ` type="crossReference"`
+	}
+	fmt.Fprintf(m.out, "<note%s n=\"%s\">%s", noteType, xmlEscape(noteCaller(n)), xmlEscape(n.Text))
+	if n.Style == "x" {
+		// The text already names the passages, the references only point
+		for _, ref := range n.References {
+			fmt.Fprintf(m.out, "<reference osisRef=\"%s\"/>", xmlEscape(bible.FormatReference(ref, bible.STYLE_OSIS)))
+		}
+	}
+	m.out.WriteString("</note>")
+}
+
+// xmlEscape escapes text for element content and attribute values.
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
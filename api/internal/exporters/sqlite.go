@@ -0,0 +1,65 @@
+package exporters
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"bibleapp.server/internal/dbmodels"
+	"bibleapp.server/internal/loader"
+	"bibleapp.server/internal/sources"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func init() {
+	Register("sqlite", WriteSQLite)
+}
+
+/**
+ * WriteSQLite writes the verses into a new SQLite database at path, with
+ * the tables of the main database, so that the version can be used on its
+ * own. A file already at path is replaced.
+ */
+func WriteSQLite(path string, meta sources.Metadata, verses []sources.Verse) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	if !dbmodels.SetupDB(db) {
+		return errors.New("setting up the database failed")
+	}
+	_, err = loader.Load(db, &verseReader{meta: meta, verses: verses}, meta, loader.Options{})
+	return err
+}
+
+// verseReader reads verses already in memory as a source.
+type verseReader struct {
+	meta   sources.Metadata
+	verses []sources.Verse
+}
+
+func (r *verseReader) Metadata() sources.Metadata {
+	return r.meta
+}
+
+func (r *verseReader) Next() (sources.Verse, error) {
+	if len(r.verses) == 0 {
+		return sources.Verse{}, io.EOF
+	}
+	v := r.verses[0]
+	r.verses = r.verses[1:]
+	return v, nil
+}
+
+func (r *verseReader) Close() error {
+	return nil
+}
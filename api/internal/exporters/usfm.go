@@ -0,0 +1,129 @@
+package exporters
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"bibleapp.server/internal/sources"
+	bible "bibleapp.server/pkg/bible_parser"
+)
+
+func init() {
+	Register("usfm", WriteUSFM)
+}
+
+/**
+ * WriteUSFM writes a USFM file per book into the directory at path, named
+ * after the position and code of the book ("01-GEN.usfm") so that reading
+ * the directory back keeps the order.
+ */
+func WriteUSFM(path string, meta sources.Metadata, verses []sources.Verse) error {
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return err
+	}
+
+	var usfm strings.Builder
+	flush := func(book bible.Book) error {
+		if usfm.Len() == 0 {
+			return nil
+		}
+		name := filepath.Join(path, fmt.Sprintf("%02d-%s.usfm", int(book)+1, book.Code()))
+		err := os.WriteFile(name, []byte(usfm.String()), 0o644)
+		usfm.Reset()
+		return err
+	}
+
+	var book bible.Book
+	var chapter uint
+	for i, v := range verses {
+		if i == 0 || v.Book != book {
+			if err := flush(book); err != nil {
+				return err
+			}
+			book, chapter = v.Book, 0
+			fmt.Fprintf(&usfm, "\\id %s %s\n", v.Book.Code(), meta.Name)
+			fmt.Fprintf(&usfm, "\\h %s\n", v.Book)
+		}
+		if v.Chapter != chapter {
+			chapter = v.Chapter
+			fmt.Fprintf(&usfm, "\\c %d\n", chapter)
+		}
+		writeUSFMVerse(&usfm, v)
+	}
+	return flush(book)
+}
+
+// writeUSFMVerse writes the headings, paragraph and text of a verse.
+func writeUSFMVerse(usfm *strings.Builder, v sources.Verse) {
+	for _, heading := range v.Headings {
+		fmt.Fprintf(usfm, "\\%s %s\n", heading.Style, heading.Text)
+	}
+	if v.Paragraph != "" {
+		fmt.Fprintf(usfm, "\\%s\n", v.Paragraph)
+	}
+
+	number := fmt.Sprint(v.Number)
+	if v.EndNumber > v.Number {
+		number += fmt.Sprintf("-%d", v.EndNumber)
+	}
+	fmt.Fprintf(usfm, "\\v %s ", number)
+	render(v, &usfmMarkup{out: usfm})
+	usfm.WriteString("\n")
+}
+
+// usfmMarkup writes verse markup as USFM, nested character styles with "+".
+type usfmMarkup struct {
+	out   *strings.Builder
+	depth int
+}
+
+func (m *usfmMarkup) marker(style string) string {
+	if m.depth > 0 {
+		return "\\+" + style
+	}
+	return "\\" + style
+}
+
+func (m *usfmMarkup) text(s string) {
+	m.out.WriteString(s)
+}
+
+func (m *usfmMarkup) open(item inline) {
+	m.out.WriteString(m.marker(item.style) + " ")
+	m.depth++
+}
+
+func (m *usfmMarkup) close(item inline) {
+	m.depth--
+	if w := item.word; w != nil {
+		// \w gracious|lemma="chanan" strong="H2603"\w*
+		var attrs []string
+		for _, attr := range []struct{ name, value string }{
+			{"lemma", w.Lemma},
+			{"strong", w.Strong},
+			{"x-morph", w.Morph},
+		} {
+			if attr.value != "" {
+				attrs = append(attrs, fmt.Sprintf("%s=%q", attr.name, attr.value))
+			}
+		}
+		if len(attrs) > 0 {
+			m.out.WriteString("|" + strings.Join(attrs, " "))
+		}
+	}
+	m.out.WriteString(m.marker(item.style) + "*")
+}
+
+func (m *usfmMarkup) note(n sources.Note) {
+	text := n.Text
+	if text == "" && len(n.References) > 0 {
+		text = bible.FormatReferenceList(n.References, bible.STYLE_FULL)
+	}
+	body := "ft"
+	if n.Style == "x" {
+		body = "xt"
+	}
+	fmt.Fprintf(m.out, "\\%s %s \\%s %s\\%s*", n.Style, noteCaller(n), body, text, n.Style)
+}
@@ -0,0 +1,117 @@
+package integrity
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"bibleapp.server/internal/sources"
+	bible "bibleapp.server/pkg/bible_parser"
+	"gorm.io/gorm"
+)
+
+// VerseTablesPath is where the verse count tables of catholic-resources.org
+// are, relative to the api directory.
+const VerseTablesPath = "../docs/resources/catholic-resources.org/*-verses*.tsv"
+
+// Flags configure the check run by the commands after an import.
+type Flags struct {
+	Schemes     string
+	VerseTables string
+	Report      string
+	Thresholds
+}
+
+// RegisterFlags defines the flags of the check on fs.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{}
+	fs.StringVar(&f.Schemes, "check-schemes", "KJV", "Versification schemes to check imported bibles against, comma separated (empty for none)")
+	fs.StringVar(&f.VerseTables, "check-verse-tables", VerseTablesPath, "Verse count tables to check imported bibles against (empty for none)")
+	fs.StringVar(&f.Report, "report", "", "Write the integrity report as JSON to this file")
+	fs.IntVar(&f.MaxErrors, "max-errors", 0, "Fail when a bible has more integrity errors (negative to never fail)")
+	fs.IntVar(&f.MaxWarnings, "max-warnings", -1, "Fail when a bible has more integrity warnings (negative to never fail)")
+	return f
+}
+
+// references loads the versifications to check against: the schemes of
+// sources.VersificationPath, then the verse count tables as "NAB".
+func (f *Flags) references() ([]*bible.Versification, error) {
+	var references []*bible.Versification
+	if names := strings.TrimSpace(f.Schemes); names != "" {
+		schemes, err := bible.LoadVersifications(sources.VersificationPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range strings.Split(names, ",") {
+			scheme, ok := schemes[strings.TrimSpace(name)]
+			if !ok {
+				return nil, fmt.Errorf("unknown versification %q, expected one of %s",
+					name, strings.Join(bible.SchemeNames(schemes), ", "))
+			}
+			references = append(references, scheme)
+		}
+	}
+
+	if f.VerseTables != "" {
+		paths, err := filepath.Glob(f.VerseTables)
+		if err != nil {
+			return nil, err
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no verse count tables match %s", f.VerseTables)
+		}
+		tables, err := bible.LoadVerseCountTables("NAB", paths...)
+		if err != nil {
+			return nil, err
+		}
+		references = append(references, tables)
+	}
+	return references, nil
+}
+
+/**
+ * Run checks the given bibles, prints the reports and writes them to the
+ * -report file. It fails when a bible has more issues than the thresholds
+ * allow, so that a bad import stops whatever runs it.
+ */
+func (f *Flags) Run(db *gorm.DB, abbreviations ...string) error {
+	references, err := f.references()
+	if err != nil {
+		return err
+	}
+
+	var reports []*Report
+	var failed []string
+	for _, abbreviation := range abbreviations {
+		report, err := Check(db, abbreviation, references...)
+		if err != nil {
+			return err
+		}
+		if err := report.WriteText(os.Stdout); err != nil {
+			return err
+		}
+		reports = append(reports, report)
+		if report.Exceeds(f.Thresholds) {
+			failed = append(failed, report.Bible)
+		}
+	}
+
+	if f.Report != "" {
+		file, err := os.Create(f.Report)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if err := WriteJSON(file, reports); err != nil {
+			return err
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%s failed the integrity check (more than %d errors or %d warnings)",
+			strings.Join(failed, ", "), f.MaxErrors, f.MaxWarnings)
+	}
+	return nil
+}
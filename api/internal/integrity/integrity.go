@@ -0,0 +1,360 @@
+// Package integrity checks the verses of a stored bible against the
+// versifications it should follow.
+package integrity
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"bibleapp.server/internal/dbmodels"
+	bible "bibleapp.server/pkg/bible_parser"
+	"gorm.io/gorm"
+)
+
+// Severity is how bad an issue is.
+type Severity uint8
+
+const (
+	SEVERITY_INFO Severity = iota
+	SEVERITY_WARNING
+	SEVERITY_ERROR
+)
+
+var severityNames = []string{"info", "warning", "error"}
+
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+// MarshalText encodes the severity as its name.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// IssueKind is what is wrong.
+type IssueKind uint8
+
+const (
+	ISSUE_DUPLICATE_VERSE IssueKind = iota // stored more than once
+	ISSUE_EMPTY_VERSE                      // without text
+	ISSUE_MISSING_VERSE                    // in the reference, not stored
+	ISSUE_EXTRA_VERSE                      // stored, not in the reference
+	ISSUE_CHAPTER_COUNT                    // another number of verses than the reference
+	ISSUE_MISSING_CHAPTER                  // of a book that is stored
+	ISSUE_EXTRA_CHAPTER
+	ISSUE_MISSING_BOOK
+	ISSUE_EXTRA_BOOK
+)
+
+var issueNames = []string{
+	"duplicate-verse",
+	"empty-verse",
+	"missing-verse",
+	"extra-verse",
+	"chapter-count",
+	"missing-chapter",
+	"extra-chapter",
+	"missing-book",
+	"extra-book",
+}
+
+// issueSeverities are the severities of the kinds of issues.
+var issueSeverities = []Severity{
+	ISSUE_DUPLICATE_VERSE: SEVERITY_ERROR,
+	ISSUE_EMPTY_VERSE:     SEVERITY_WARNING,
+	ISSUE_MISSING_VERSE:   SEVERITY_WARNING,
+	ISSUE_EXTRA_VERSE:     SEVERITY_WARNING,
+	ISSUE_CHAPTER_COUNT:   SEVERITY_INFO,
+	ISSUE_MISSING_CHAPTER: SEVERITY_ERROR,
+	ISSUE_EXTRA_CHAPTER:   SEVERITY_WARNING,
+	ISSUE_MISSING_BOOK:    SEVERITY_INFO,
+	ISSUE_EXTRA_BOOK:      SEVERITY_INFO,
+}
+
+func (k IssueKind) String() string {
+	return issueNames[k]
+}
+
+// MarshalText encodes the kind as its name.
+func (k IssueKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+/**
+ * Issue is a problem found with a bible. Reference names the versification
+ * the bible was compared with, for the issues that come from comparing.
+ * Expected and Found are numbers of verses, chapters or copies, depending
+ * on the kind.
+ */
+type Issue struct {
+	Kind      IssueKind  `json:"kind"`
+	Severity  Severity   `json:"severity"`
+	Reference string     `json:"reference,omitempty"`
+	Book      bible.Book `json:"book"`
+	Chapter   uint       `json:"chapter,omitempty"`
+	Verse     uint       `json:"verse,omitempty"`
+	Expected  uint       `json:"expected,omitempty"`
+	Found     uint       `json:"found,omitempty"`
+}
+
+// String describes the issue, e.g. "GEN 1:3 is missing".
+func (i Issue) String() string {
+	place := i.Book.Code()
+	if i.Chapter > 0 {
+		place += fmt.Sprintf(" %d", i.Chapter)
+	}
+	if i.Verse > 0 {
+		place += fmt.Sprintf(":%d", i.Verse)
+	}
+
+	switch i.Kind {
+	case ISSUE_DUPLICATE_VERSE:
+		return fmt.Sprintf("%s is stored %d times", place, i.Found)
+	case ISSUE_EMPTY_VERSE:
+		return fmt.Sprintf("%s has no text", place)
+	case ISSUE_MISSING_VERSE, ISSUE_MISSING_CHAPTER, ISSUE_MISSING_BOOK:
+		return fmt.Sprintf("%s is missing", place)
+	case ISSUE_EXTRA_VERSE, ISSUE_EXTRA_CHAPTER, ISSUE_EXTRA_BOOK:
+		return fmt.Sprintf("%s is not in the versification", place)
+	}
+	return fmt.Sprintf("%s has %d verses instead of %d", place, i.Found, i.Expected)
+}
+
+// Report is the outcome of checking a bible.
+type Report struct {
+	Bible      string   `json:"bible"`
+	References []string `json:"references"`
+	Books      int      `json:"books"`
+	Chapters   int      `json:"chapters"`
+	Verses     int      `json:"verses"`
+	Errors     int      `json:"errors"`
+	Warnings   int      `json:"warnings"`
+	Issues     []Issue  `json:"issues"`
+}
+
+// Thresholds are the numbers of issues a bible may have and still pass.
+// Negative numbers never fail.
+type Thresholds struct {
+	MaxErrors   int
+	MaxWarnings int
+}
+
+// Exceeds reports whether the report has more issues than allowed.
+func (r *Report) Exceeds(t Thresholds) bool {
+	return (t.MaxErrors >= 0 && r.Errors > t.MaxErrors) ||
+		(t.MaxWarnings >= 0 && r.Warnings > t.MaxWarnings)
+}
+
+// WriteText writes the errors and warnings of the report for people, most
+// severe first. Notes are only counted; the JSON report has them.
+func (r *Report) WriteText(w io.Writer) error {
+	references := strings.Join(r.References, ", ")
+	if references == "" {
+		references = "no versification"
+	}
+	notes := len(r.Issues) - r.Errors - r.Warnings
+	_, err := fmt.Fprintf(w, "%s against %s: %d books, %d chapters, %d verses, %d errors, %d warnings, %d notes\n",
+		r.Bible, references, r.Books, r.Chapters, r.Verses, r.Errors, r.Warnings, notes)
+	if err != nil {
+		return err
+	}
+
+	issues := append([]Issue(nil), r.Issues...)
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Severity > issues[j].Severity
+	})
+	for _, issue := range issues {
+		if issue.Severity == SEVERITY_INFO {
+			break
+		}
+		reference := issue.Reference
+		if reference == "" {
+			reference = "-"
+		}
+		if _, err := fmt.Fprintf(w, "%-7s %-10s %s\n", issue.Severity, reference, issue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes reports for machines, as a JSON array.
+func WriteJSON(w io.Writer, reports []*Report) error {
+	if reports == nil {
+		reports = []*Report{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(reports)
+}
+
+// storedVerse is a verse of the bible being checked.
+type storedVerse struct {
+	Code      string
+	Chapter   uint
+	Number    uint
+	EndNumber uint
+	Text      string
+}
+
+/**
+ * Check checks the bible with the given abbreviation. It reports verses
+ * stored more than once and verses without text, then compares the books
+ * of the bible with each of the references: their chapters, and the verses
+ * of every chapter. Books only one side has are noted but not held against
+ * the bible, as versifications cover canons the bible may not follow.
+ */
+func Check(db *gorm.DB, abbreviation string, references ...*bible.Versification) (*Report, error) {
+	var bibleDB dbmodels.Bible
+	err := db.Where("abbreviation = ?", strings.ToLower(abbreviation)).Take(&bibleDB).Error
+	if err != nil {
+		return nil, fmt.Errorf("bible %s: %w", abbreviation, err)
+	}
+
+	var verses []storedVerse
+	err = db.Model(&dbmodels.Verse{}).
+		Select("books.code, chapters.number AS chapter, verses.number, verses.end_number, verses.text").
+		Joins("JOIN chapters ON chapters.id = verses.chapter_id AND chapters.deleted_at IS NULL").
+		Joins("JOIN books ON books.id = chapters.book_id AND books.deleted_at IS NULL").
+		Where("books.bible_id = ?", bibleDB.ID).
+		Order("books.position, chapters.number, verses.number").
+		Scan(&verses).Error
+	if err != nil {
+		return nil, fmt.Errorf("bible %s: %w", abbreviation, err)
+	}
+	return check(bibleDB.Abbreviation, verses, references), nil
+}
+
+// check builds the report of a bible from its verses.
+func check(name string, verses []storedVerse, references []*bible.Versification) *Report {
+	r := &Report{Bible: name, References: []string{}, Issues: []Issue{}, Verses: len(verses)}
+
+	// How many times each verse is stored, counting bridges for every
+	// verse they cover, by book and chapter
+	stored := map[bible.Book]map[uint]map[uint]uint{}
+	var books []bible.Book
+	for _, v := range verses {
+		book, ok := bible.BookFromCode(v.Code)
+		if !ok {
+			continue
+		}
+		if stored[book] == nil {
+			stored[book] = map[uint]map[uint]uint{}
+			books = append(books, book)
+		}
+		if stored[book][v.Chapter] == nil {
+			stored[book][v.Chapter] = map[uint]uint{}
+			r.Chapters++
+		}
+
+		end := v.EndNumber
+		if end < v.Number {
+			end = v.Number
+		}
+		for n := v.Number; n <= end; n++ {
+			stored[book][v.Chapter][n]++
+			if count := stored[book][v.Chapter][n]; count == 2 {
+				r.add(Issue{Kind: ISSUE_DUPLICATE_VERSE, Book: book, Chapter: v.Chapter, Verse: n})
+			}
+		}
+		if strings.TrimSpace(v.Text) == "" {
+			r.add(Issue{Kind: ISSUE_EMPTY_VERSE, Book: book, Chapter: v.Chapter, Verse: v.Number})
+		}
+	}
+	r.Books = len(books)
+
+	// Fill in how many times the duplicates are stored
+	for i, issue := range r.Issues {
+		if issue.Kind == ISSUE_DUPLICATE_VERSE {
+			r.Issues[i].Found = stored[issue.Book][issue.Chapter][issue.Verse]
+			r.Issues[i].Expected = 1
+		}
+	}
+
+	for _, reference := range references {
+		r.References = append(r.References, reference.Name)
+		r.compare(reference, books, stored)
+	}
+	return r
+}
+
+// compare compares the stored verses with a reference versification.
+func (r *Report) compare(reference *bible.Versification, books []bible.Book, stored map[bible.Book]map[uint]map[uint]uint) {
+	add := func(issue Issue) {
+		issue.Reference = reference.Name
+		r.add(issue)
+	}
+
+	for _, book := range reference.Books() {
+		if stored[book] == nil {
+			add(Issue{Kind: ISSUE_MISSING_BOOK, Book: book})
+		}
+	}
+
+	for _, book := range books {
+		if !reference.HasBook(book) {
+			add(Issue{Kind: ISSUE_EXTRA_BOOK, Book: book})
+			continue
+		}
+
+		counts := reference.VerseCounts(book)
+		for chapter := uint(1); chapter <= uint(len(counts)); chapter++ {
+			found := stored[book][chapter]
+			if found == nil {
+				add(Issue{Kind: ISSUE_MISSING_CHAPTER, Book: book, Chapter: chapter})
+				continue
+			}
+
+			expected := counts[chapter-1]
+			for verse := uint(1); verse <= expected; verse++ {
+				if found[verse] == 0 {
+					add(Issue{Kind: ISSUE_MISSING_VERSE, Book: book, Chapter: chapter, Verse: verse})
+				}
+			}
+			for _, verse := range sortedKeys(found) {
+				// Verse 0 holds what comes before verse 1, e.g. Psalm titles
+				if verse > expected {
+					add(Issue{Kind: ISSUE_EXTRA_VERSE, Book: book, Chapter: chapter, Verse: verse})
+				}
+			}
+
+			numbered := uint(len(found))
+			if found[0] > 0 {
+				numbered--
+			}
+			if numbered != expected {
+				add(Issue{Kind: ISSUE_CHAPTER_COUNT, Book: book, Chapter: chapter, Expected: expected, Found: numbered})
+			}
+		}
+
+		for _, chapter := range sortedKeys(stored[book]) {
+			if chapter > uint(len(counts)) {
+				add(Issue{Kind: ISSUE_EXTRA_CHAPTER, Book: book, Chapter: chapter})
+			}
+		}
+	}
+}
+
+// add records an issue with the severity of its kind.
+func (r *Report) add(issue Issue) {
+	issue.Severity = issueSeverities[issue.Kind]
+	switch issue.Severity {
+	case SEVERITY_ERROR:
+		r.Errors++
+	case SEVERITY_WARNING:
+		r.Warnings++
+	}
+	r.Issues = append(r.Issues, issue)
+}
+
+func sortedKeys[T any](m map[uint]T) []uint {
+	keys := make([]uint, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
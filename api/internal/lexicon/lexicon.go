@@ -0,0 +1,286 @@
+// Package lexicon stores lexicons keyed by Strong's numbers and links the
+// tagged words of the stored bibles to their entries.
+package lexicon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"bibleapp.server/internal/dbmodels"
+	"bibleapp.server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Rows are inserted and looked up this many at a time.
+const rowsPerInsert = 1000
+
+/**
+ * Normalize returns a Strong's number as the lexicons key it: the testament
+ * letter in upper case followed by the number without padding. Sources also
+ * write "H0430", "h430", "strong:H430" or add a letter for a distinction
+ * Strong did not make ("H1254a"), which is dropped.
+ */
+func Normalize(strong string) (string, bool) {
+	strong = strings.TrimPrefix(strings.TrimSpace(strong), "strong:")
+	if len(strong) < 2 {
+		return "", false
+	}
+	prefix := strings.ToUpper(strong[:1])
+	if prefix != "H" && prefix != "G" {
+		return "", false
+	}
+	digits := strong[1:]
+	end := 0
+	for end < len(digits) && digits[end] >= '0' && digits[end] <= '9' {
+		end++
+	}
+	if end == 0 {
+		return "", false
+	}
+	for _, r := range digits[end:] {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z') {
+			return "", false
+		}
+	}
+	number := strings.TrimLeft(digits[:end], "0")
+	if number == "" {
+		return "", false
+	}
+	return prefix + number, true
+}
+
+// Numbers returns the normalized Strong's numbers of a space separated list,
+// as stored in VerseWord.Strong, skipping anything that is not one.
+func Numbers(strongs string) []string {
+	var numbers []string
+	for _, field := range strings.Fields(strongs) {
+		if strong, ok := Normalize(field); ok {
+			numbers = append(numbers, strong)
+		}
+	}
+	return numbers
+}
+
+/**
+ * Import stores the entries of a lexicon, creating the lexicon or updating
+ * it by abbreviation. Entries are matched by Strong's number so that they
+ * keep their rows, and the words linked to them their links; entries no
+ * longer in the lexicon are removed with their links. Soft deleted rows are
+ * brought back, since they still hold their keys in the unique indexes.
+ * Returns the number of entries removed.
+ */
+func Import(db *gorm.DB, lexicon models.Lexicon, entries []models.LexiconEntry) (int, error) {
+	removed := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var lexiconDB dbmodels.Lexicon
+		err := tx.Unscoped().Where("abbreviation = ?", lexicon.Abbreviation).Take(&lexiconDB).Error
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			lexiconDB.Lexicon = lexicon
+			if err := tx.Create(&lexiconDB).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			lexiconDB.Lexicon = lexicon
+			lexiconDB.DeletedAt = gorm.DeletedAt{}
+			if err := tx.Unscoped().Save(&lexiconDB).Error; err != nil {
+				return err
+			}
+		}
+
+		rows := make([]dbmodels.LexiconEntry, len(entries))
+		numbers := map[string]bool{}
+		for i, entry := range entries {
+			entry.LexiconID = lexiconDB.ID
+			rows[i] = dbmodels.LexiconEntry{LexiconEntry: entry}
+			numbers[entry.Strong] = true
+		}
+		if len(rows) > 0 {
+			upsert := clause.OnConflict{
+				Columns: []clause.Column{{Name: "lexicon_id"}, {Name: "strong"}},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"lemma", "transliteration", "pronunciation", "derivation",
+					"definition", "kjv_definition", "updated_at", "deleted_at",
+				}),
+			}
+			if err := tx.Clauses(upsert).CreateInBatches(&rows, rowsPerInsert).Error; err != nil {
+				return err
+			}
+		}
+
+		var stored []dbmodels.LexiconEntry
+		err = tx.Unscoped().Select("id", "strong").Where("lexicon_id = ?", lexiconDB.ID).Find(&stored).Error
+		if err != nil {
+			return err
+		}
+		var stale []uint
+		for _, entry := range stored {
+			if !numbers[entry.Strong] {
+				stale = append(stale, entry.ID)
+			}
+		}
+		removed = len(stale)
+		return deleteEntries(tx, stale)
+	})
+	return removed, err
+}
+
+// deleteEntries deletes lexicon entries and the links to them.
+func deleteEntries(tx *gorm.DB, ids []uint) error {
+	for start := 0; start < len(ids); start += rowsPerInsert {
+		end := start + rowsPerInsert
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunk := ids[start:end]
+		if err := tx.Unscoped().Where("lexicon_entry_id IN ?", chunk).Delete(&dbmodels.VerseWordEntry{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Delete(&dbmodels.LexiconEntry{}, chunk).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taggedToken is a word of a verse tagged with Strong's numbers.
+type taggedToken struct {
+	VerseID  uint
+	Position uint
+	Strong   string
+}
+
+// Linked counts what Link did.
+type Linked struct {
+	// Tagged words linked to at least one entry
+	Tokens int
+	// Links between tagged words and entries, new or not
+	Links int
+	// Strong's numbers without an entry, sorted
+	Unknown []string
+	// Interlinear tokens given the lemma of their entry
+	Lemmas int
+}
+
+/**
+ * Link links the tagged words of the bibles with the given abbreviations
+ * (all bibles when none are given) to the lexicon entries of their Strong's
+ * numbers, through verse_word_entries: a word is linked where it occurs,
+ * since the same word is tagged differently from verse to verse. Links
+ * already there are kept, so linking again only adds what is missing; the
+ * loader removes the links of the verses it rewrites. Interlinear tokens
+ * without a lemma get the lemma of the entry of their Strong's number.
+ */
+func Link(db *gorm.DB, abbreviations ...string) (Linked, error) {
+	var linked Linked
+
+	var entries []dbmodels.LexiconEntry
+	if err := db.Select("id", "strong").Find(&entries).Error; err != nil {
+		return linked, err
+	}
+	if len(entries) == 0 {
+		return linked, nil
+	}
+	entryIDs := map[string][]uint{}
+	for _, entry := range entries {
+		entryIDs[entry.Strong] = append(entryIDs[entry.Strong], entry.ID)
+	}
+
+	query := db.Model(&dbmodels.Verse{})
+	if len(abbreviations) > 0 {
+		lowered := make([]string, len(abbreviations))
+		for i, abbreviation := range abbreviations {
+			lowered[i] = strings.ToLower(abbreviation)
+		}
+		query = query.
+			Joins("JOIN chapters ON chapters.id = verses.chapter_id").
+			Joins("JOIN books ON books.id = chapters.book_id").
+			Joins("JOIN bibles ON bibles.id = books.bible_id").
+			Where("bibles.abbreviation IN ?", lowered)
+	}
+	var verseIDs []uint
+	if err := query.Order("verses.id").Pluck("verses.id", &verseIDs).Error; err != nil {
+		return linked, err
+	}
+
+	unknown := map[string]bool{}
+	for start := 0; start < len(verseIDs); start += rowsPerInsert {
+		end := start + rowsPerInsert
+		if end > len(verseIDs) {
+			end = len(verseIDs)
+		}
+		var tokens []taggedToken
+		err := db.Model(&dbmodels.VerseWord{}).
+			Select("verse_id", "position", "strong").
+			Where("verse_id IN ?", verseIDs[start:end]).
+			Where("strong <> ''").
+			Scan(&tokens).Error
+		if err != nil {
+			return linked, err
+		}
+
+		var links []dbmodels.VerseWordEntry
+		for _, token := range tokens {
+			found := false
+			for _, strong := range Numbers(token.Strong) {
+				ids, ok := entryIDs[strong]
+				if !ok {
+					unknown[strong] = true
+					continue
+				}
+				found = true
+				for _, id := range ids {
+					links = append(links, dbmodels.VerseWordEntry{
+						VerseWordEntry: models.VerseWordEntry{
+							VerseID:        token.VerseID,
+							Position:       token.Position,
+							LexiconEntryID: id,
+						},
+					})
+				}
+			}
+			if found {
+				linked.Tokens++
+			}
+		}
+		linked.Links += len(links)
+
+		if err := insertLinks(db, links); err != nil {
+			return linked, fmt.Errorf("linking words: %w", err)
+		}
+
+		lemma := db.Model(&dbmodels.LexiconEntry{}).Select("lemma").
+			Where("lexicon_entries.strong = interlinear_tokens.strong").Order("id").Limit(1)
+		result := db.Model(&dbmodels.InterlinearToken{}).
+			Where("verse_id IN ?", verseIDs[start:end]).
+			Where("lemma = '' AND strong IN (?)", db.Model(&dbmodels.LexiconEntry{}).Select("strong")).
+			Update("lemma", lemma)
+		if result.Error != nil {
+			return linked, fmt.Errorf("filling lemmas: %w", result.Error)
+		}
+		linked.Lemmas += int(result.RowsAffected)
+	}
+
+	for strong := range unknown {
+		linked.Unknown = append(linked.Unknown, strong)
+	}
+	sort.Strings(linked.Unknown)
+	return linked, nil
+}
+
+// insertLinks inserts the links not stored yet and brings back the soft
+// deleted ones.
+func insertLinks(db *gorm.DB, links []dbmodels.VerseWordEntry) error {
+	if len(links) == 0 {
+		return nil
+	}
+	upsert := clause.OnConflict{
+		Columns:   []clause.Column{{Name: "verse_id"}, {Name: "position"}, {Name: "lexicon_entry_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"deleted_at"}),
+	}
+	return db.Clauses(upsert).CreateInBatches(&links, rowsPerInsert).Error
+}
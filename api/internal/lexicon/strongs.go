@@ -0,0 +1,83 @@
+package lexicon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"bibleapp.server/internal/models"
+)
+
+/**
+ * strongsEntry is an entry of the openscriptures Strong's dictionaries
+ * (https://github.com/openscriptures/strongs). The Hebrew dictionary spells
+ * the transliteration xlit and the Greek one translit.
+ */
+type strongsEntry struct {
+	Lemma      string `json:"lemma"`
+	Xlit       string `json:"xlit"`
+	Translit   string `json:"translit"`
+	Pron       string `json:"pron"`
+	Derivation string `json:"derivation"`
+	StrongsDef string `json:"strongs_def"`
+	KJVDef     string `json:"kjv_def"`
+}
+
+/**
+ * ReadStrongs reads an openscriptures Strong's dictionary, either the
+ * JavaScript file ("var strongsGreekDictionary = {...};") or the bare JSON
+ * object it assigns. Entries are returned in the order of their numbers.
+ */
+func ReadStrongs(path string) ([]models.LexiconEntry, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// Skip the license comment and the assignment
+	start := bytes.Index(content, []byte("= {"))
+	if start >= 0 {
+		start += len("= ")
+	} else if start = bytes.IndexByte(content, '{'); start < 0 {
+		return nil, fmt.Errorf("%s: no dictionary found", path)
+	}
+
+	var dictionary map[string]strongsEntry
+	if err := json.NewDecoder(bytes.NewReader(content[start:])).Decode(&dictionary); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	entries := make([]models.LexiconEntry, 0, len(dictionary))
+	for number, e := range dictionary {
+		strong, ok := Normalize(number)
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid Strong's number %q", path, number)
+		}
+		transliteration := e.Xlit
+		if transliteration == "" {
+			transliteration = e.Translit
+		}
+		entries = append(entries, models.LexiconEntry{
+			Strong:          strong,
+			Lemma:           strings.TrimSpace(e.Lemma),
+			Transliteration: strings.TrimSpace(transliteration),
+			Pronunciation:   strings.TrimSpace(e.Pron),
+			Derivation:      strings.TrimSpace(e.Derivation),
+			Definition:      strings.TrimSpace(e.StrongsDef),
+			KJVDefinition:   strings.TrimSpace(e.KJVDef),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i].Strong, entries[j].Strong
+		if a[0] != b[0] {
+			return a[0] > b[0] // Hebrew first
+		}
+		n, _ := strconv.Atoi(a[1:])
+		m, _ := strconv.Atoi(b[1:])
+		return n < m
+	})
+	return entries, nil
+}
@@ -0,0 +1,400 @@
+// Package loader stores verses read from a source in the database.
+package loader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"bibleapp.server/internal/dbmodels"
+	"bibleapp.server/internal/models"
+	"bibleapp.server/internal/sources"
+	bible "bibleapp.server/pkg/bible_parser"
+	"gorm.io/gorm"
+)
+
+// Options tunes Load. The zero value loads with the defaults.
+type Options struct {
+	// Verses stored per transaction (default 500). Batches always hold
+	// whole chapters.
+	BatchSize int
+	// Called after every batch.
+	Progress func(Progress)
+	// Compare the source with the database without writing anything.
+	DryRun bool
+	// Also remove the books the source does not have. Otherwise only the
+	// chapters missing from the books it has are removed, so that a source
+	// holding a single book leaves the rest of the version alone.
+	Prune bool
+	// Called for every verse added, changed or removed.
+	Changes func(Change)
+}
+
+// Progress reports how far a Load has got.
+type Progress struct {
+	Verses    int // read from the source
+	Unchanged int // in chapters identical to the stored ones
+	Added     int
+	Changed   int
+	Removed   int
+	Links     int // verse to word links written
+	NewWords  int // words not in the database before
+	Elapsed   time.Duration
+}
+
+// ChangeKind is what a Load does to a verse.
+type ChangeKind uint8
+
+const (
+	CHANGE_ADDED ChangeKind = iota
+	CHANGE_CHANGED
+	CHANGE_REMOVED
+)
+
+// String returns "added", "changed" or "removed".
+func (k ChangeKind) String() string {
+	switch k {
+	case CHANGE_ADDED:
+		return "added"
+	case CHANGE_CHANGED:
+		return "changed"
+	}
+	return "removed"
+}
+
+/**
+ * Change is a verse that differs between the source and the database. Old
+ * is the stored text (empty for added verses) and New the text read (empty
+ * for removed verses). A changed verse may differ in its markup only.
+ */
+type Change struct {
+	Kind    ChangeKind
+	Book    bible.Book
+	Chapter uint
+	Verse   uint
+	Old     string
+	New     string
+}
+
+// String returns the change as a diff line, e.g. "+ GEN 1:1 In the beginning".
+func (c Change) String() string {
+	sign, text := "+", c.New
+	switch c.Kind {
+	case CHANGE_CHANGED:
+		sign = "~"
+	case CHANGE_REMOVED:
+		sign, text = "-", c.Old
+	}
+	return fmt.Sprintf("%s %s %d:%d %s", sign, c.Book.Code(), c.Chapter, c.Verse, text)
+}
+
+// Rows sent per INSERT statement, well within the Postgres limit of 65535
+// parameters for every table.
+const rowsPerInsert = 1000
+
+const defaultBatchSize = 500
+
+// preparedVerse is a verse split into words, ready to be stored.
+type preparedVerse struct {
+	verse sources.Verse
+	words []wordToken
+	hash  string
+}
+
+// preparedChapter is every verse the source has for a chapter.
+type preparedChapter struct {
+	book    bible.Book
+	chapter uint
+	verses  []preparedVerse
+	hash    string
+}
+
+// storedChapter is a chapter already in the database.
+type storedChapter struct {
+	id   uint
+	hash string
+	seen bool
+}
+
+// loader holds the state of one Load. The IDs of rows already stored are
+// cached so that each is looked up or created once.
+type loader struct {
+	opts     Options
+	bibleID  uint
+	names    *bible.BookNames
+	bookIDs  map[bible.Book]uint
+	chapters map[bible.Book]map[uint]*storedChapter
+	wordIDs  map[string]uint
+	progress Progress
+}
+
+/**
+ * Load stores every verse of reader, with the books and chapters holding
+ * them, as the bible described by meta. It returns how many verses were
+ * read, added, changed and removed.
+ *
+ * Loading is idempotent: the bible is identified by its abbreviation and
+ * its verses by book, chapter and verse number. Each verse and chapter
+ * keeps a hash of its content as read, so that chapters which have not
+ * changed are skipped and only the verses which have are rewritten. Verses
+ * and chapters the source no longer has are removed once it has been read
+ * completely. As each chapter is stored in the same transaction as its
+ * hash, an interrupted load picks up where it stopped when run again.
+ *
+ * The source is read, split into words and hashed on its own goroutine
+ * while the previous batch is being stored. Each batch is stored in one
+ * transaction with multi-row inserts; words are looked up in an in-memory
+ * cache of the words table, loaded once up front.
+ */
+func Load(db *gorm.DB, reader sources.SourceReader, meta sources.Metadata, opts Options) (Progress, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	start := time.Now()
+
+	l := &loader{
+		opts:     opts,
+		names:    bible.NamesForLocale(meta.Language),
+		bookIDs:  map[bible.Book]uint{},
+		chapters: map[bible.Book]map[uint]*storedChapter{},
+		wordIDs:  map[string]uint{},
+	}
+	if err := l.findBible(db, meta); err != nil {
+		return l.progress, fmt.Errorf("bible %s: %w", meta.Abbreviation, err)
+	}
+	if err := l.loadChapters(db); err != nil {
+		return l.progress, fmt.Errorf("loading chapters: %w", err)
+	}
+	if !opts.DryRun {
+		if err := l.loadWords(db); err != nil {
+			return l.progress, fmt.Errorf("loading words: %w", err)
+		}
+	}
+
+	batches := make(chan []preparedChapter, 4)
+	readErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(batches)
+		readErr <- readBatches(reader, opts.BatchSize, batches, done)
+	}()
+
+	for batch := range batches {
+		err := l.transaction(db, func(tx *gorm.DB) error {
+			return l.store(tx, batch)
+		})
+		if err != nil {
+			first := batch[0]
+			return l.progress, fmt.Errorf("storing chapters from %s %d: %w", first.book, first.chapter, err)
+		}
+		l.progress.Elapsed = time.Since(start)
+		if opts.Progress != nil {
+			opts.Progress(l.progress)
+		}
+	}
+	if err := <-readErr; err != nil {
+		return l.progress, err
+	}
+
+	// The source has been read completely: whatever it did not have is gone
+	// from the books it has
+	err := l.transaction(db, l.removeUnseen)
+	l.progress.Elapsed = time.Since(start)
+	return l.progress, err
+}
+
+// transaction runs f in a transaction, or straight on db for a dry run,
+// which only reads.
+func (l *loader) transaction(db *gorm.DB, f func(tx *gorm.DB) error) error {
+	if l.opts.DryRun {
+		return f(db)
+	}
+	return db.Transaction(f)
+}
+
+/**
+ * findBible finds the bible by its abbreviation, creating it or updating
+ * its description. A soft deleted bible still holds its abbreviation in the
+ * unique index, so it is brought back rather than created again.
+ */
+func (l *loader) findBible(db *gorm.DB, meta sources.Metadata) error {
+	fields := models.Bible{
+		Name:         meta.Name,
+		Abbreviation: strings.ToLower(meta.Abbreviation),
+		Language:     meta.Language,
+		Description:  meta.Description,
+	}
+
+	var bibleDB dbmodels.Bible
+	err := db.Unscoped().Where("abbreviation = ?", fields.Abbreviation).Take(&bibleDB).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if l.opts.DryRun {
+			return nil
+		}
+		bibleDB.Bible = fields
+		err = db.Create(&bibleDB).Error
+		l.bibleID = bibleDB.ID
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	l.bibleID = bibleDB.ID
+	if l.opts.DryRun || (bibleDB.Bible == fields && !bibleDB.DeletedAt.Valid) {
+		return nil
+	}
+	return db.Unscoped().Model(&bibleDB).Select("name", "language", "description", "deleted_at").
+		Updates(dbmodels.Bible{Bible: fields}).Error
+}
+
+// loadChapters caches the books and chapters already stored for the bible.
+func (l *loader) loadChapters(db *gorm.DB) error {
+	if l.bibleID == 0 {
+		return nil
+	}
+
+	var books []dbmodels.Book
+	if err := db.Where("bible_id = ?", l.bibleID).Preload("Chapters").Find(&books).Error; err != nil {
+		return err
+	}
+	for _, bookDB := range books {
+		book, ok := bible.BookFromCode(bookDB.Code)
+		if !ok {
+			continue
+		}
+		l.bookIDs[book] = bookDB.ID
+		l.chapters[book] = map[uint]*storedChapter{}
+		for _, chapterDB := range bookDB.Chapters {
+			l.chapters[book][chapterDB.Number] = &storedChapter{id: chapterDB.ID, hash: chapterDB.Hash}
+		}
+	}
+	return nil
+}
+
+// loadWords fills the word cache from the words table.
+func (l *loader) loadWords(db *gorm.DB) error {
+	var words []dbmodels.Word
+	if err := db.Select("id", "word").Find(&words).Error; err != nil {
+		return err
+	}
+	for _, word := range words {
+		l.wordIDs[word.Word.Word] = word.ID
+	}
+	return nil
+}
+
+/**
+ * readBatches reads the source into batches of whole chapters until it runs
+ * out or done is closed. A batch is sent once it holds size verses or more.
+ */
+func readBatches(reader sources.SourceReader, size int, batches chan<- []preparedChapter, done <-chan struct{}) error {
+	var batch []preparedChapter
+	var chapter *preparedChapter
+	verses := 0
+
+	send := func() bool {
+		if len(batch) == 0 {
+			return true
+		}
+		select {
+		case batches <- batch:
+			batch, verses = nil, 0
+			return true
+		case <-done:
+			return false
+		}
+	}
+	endChapter := func() {
+		if chapter == nil {
+			return
+		}
+		chapter.hash = chapterHash(chapter.verses)
+		batch = append(batch, *chapter)
+		verses += len(chapter.verses)
+		chapter = nil
+	}
+
+	for {
+		v, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			endChapter()
+			send()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if chapter != nil && (chapter.book != v.Book || chapter.chapter != v.Chapter) {
+			endChapter()
+			if verses >= size && !send() {
+				return nil
+			}
+		}
+		if chapter == nil {
+			chapter = &preparedChapter{book: v.Book, chapter: v.Chapter}
+		}
+		hash, err := verseHash(v)
+		if err != nil {
+			return err
+		}
+		chapter.verses = append(chapter.verses, preparedVerse{verse: v, words: splitWords(v.Text), hash: hash})
+	}
+}
+
+// verseHash hashes everything read for a verse.
+func verseHash(v sources.Verse) (string, error) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// chapterHash hashes the hashes of the verses of a chapter.
+func chapterHash(verses []preparedVerse) string {
+	h := sha256.New()
+	for _, v := range verses {
+		h.Write([]byte(v.hash))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// wordToken is a word of a verse and where it sits in the verse text.
+type wordToken struct {
+	word       string
+	start, end int
+}
+
+// splitWords splits verse text into words, dropping the punctuation around
+// them.
+func splitWords(text string) []wordToken {
+	var tokens []wordToken
+	start := -1
+	for i, r := range text + " " {
+		if !unicode.IsSpace(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start < 0 {
+			continue
+		}
+		word := strings.TrimFunc(text[start:i], unicode.IsPunct)
+		if word != "" {
+			tokens = append(tokens, wordToken{word: word, start: start, end: i})
+		}
+		start = -1
+	}
+	return tokens
+}
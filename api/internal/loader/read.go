@@ -0,0 +1,237 @@
+package loader
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"bibleapp.server/internal/dbmodels"
+	"bibleapp.server/internal/sources"
+	bible "bibleapp.server/pkg/bible_parser"
+	"gorm.io/gorm"
+)
+
+// taggedLink is a word of a verse carrying lexical attributes.
+type taggedLink struct {
+	VerseID  uint
+	Position uint
+	Strong   string
+	Lemma    string
+	Morph    string
+}
+
+/**
+ * Read reads the bible with the given abbreviation back as the verses it was
+ * loaded from, in order, with their headings, notes, character styles and
+ * interlinear tokens.
+ *
+ * Words are stored one by one, so a tagged word is read back as the "\w"
+ * span that held it when there is one, and as the single word otherwise.
+ */
+func Read(db *gorm.DB, abbreviation string) (sources.Metadata, []sources.Verse, error) {
+	var meta sources.Metadata
+	var bibleDB dbmodels.Bible
+	err := db.Where("abbreviation = ?", strings.ToLower(abbreviation)).Take(&bibleDB).Error
+	if err != nil {
+		return meta, nil, fmt.Errorf("bible %s: %w", abbreviation, err)
+	}
+	meta = sources.Metadata{
+		Abbreviation: bibleDB.Abbreviation,
+		Name:         bibleDB.Name,
+		Language:     bibleDB.Language,
+		Description:  bibleDB.Description,
+	}
+
+	var books []dbmodels.Book
+	err = db.Where("bible_id = ?", bibleDB.ID).Order("position").Preload("Chapters").Find(&books).Error
+	if err != nil {
+		return meta, nil, err
+	}
+
+	var verses []sources.Verse
+	for _, bookDB := range books {
+		book, ok := bible.BookFromCode(bookDB.Code)
+		if !ok {
+			return meta, nil, fmt.Errorf("unknown book code %q", bookDB.Code)
+		}
+		read, err := readBook(db, book, bookDB.Chapters)
+		if err != nil {
+			return meta, nil, fmt.Errorf("%s: %w", book, err)
+		}
+		verses = append(verses, read...)
+	}
+	return meta, verses, nil
+}
+
+// readBook reads the verses of the chapters of a book.
+func readBook(db *gorm.DB, book bible.Book, chapters []dbmodels.Chapter) ([]sources.Verse, error) {
+	if len(chapters) == 0 {
+		return nil, nil
+	}
+	chapterNumbers := map[uint]uint{}
+	chapterIDs := make([]uint, len(chapters))
+	for i, chapter := range chapters {
+		chapterNumbers[chapter.ID] = chapter.Number
+		chapterIDs[i] = chapter.ID
+	}
+
+	var rows []dbmodels.Verse
+	err := db.Where("chapter_id IN ?", chapterIDs).
+		Preload("Headings", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("Notes", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Spans", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Tokens", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := chapterNumbers[rows[i].ChapterID], chapterNumbers[rows[j].ChapterID]
+		if a != b {
+			return a < b
+		}
+		if rows[i].Number != rows[j].Number {
+			return rows[i].Number < rows[j].Number
+		}
+		return rows[i].ID < rows[j].ID
+	})
+
+	verseIDs := make([]uint, len(rows))
+	for i, row := range rows {
+		verseIDs[i] = row.ID
+	}
+	links := map[uint][]taggedLink{}
+	for start := 0; start < len(verseIDs); start += rowsPerInsert {
+		end := start + rowsPerInsert
+		if end > len(verseIDs) {
+			end = len(verseIDs)
+		}
+		var tagged []taggedLink
+		err := db.Model(&dbmodels.VerseWord{}).
+			Select("verse_id", "position", "strong", "lemma", "morph").
+			Where("verse_id IN ?", verseIDs[start:end]).
+			Where("strong <> '' OR lemma <> '' OR morph <> ''").
+			Order("verse_id, position").
+			Scan(&tagged).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, link := range tagged {
+			links[link.VerseID] = append(links[link.VerseID], link)
+		}
+	}
+
+	verses := make([]sources.Verse, len(rows))
+	for i, row := range rows {
+		v := sources.Verse{
+			Book:      book,
+			Chapter:   chapterNumbers[row.ChapterID],
+			Number:    row.Number,
+			EndNumber: row.EndNumber,
+			Text:      row.Text,
+			Paragraph: row.Paragraph,
+		}
+		for _, heading := range row.Headings {
+			v.Headings = append(v.Headings, sources.Heading{Style: heading.Style, Text: heading.Text})
+		}
+		for _, note := range row.Notes {
+			n := sources.Note{
+				Style:  note.Style,
+				Caller: note.Caller,
+				Offset: note.Offset,
+				Text:   note.Text,
+			}
+			references, err := readTargets(note.Targets)
+			if err != nil {
+				return nil, fmt.Errorf("note of %s %d:%d: %w", book, v.Chapter, v.Number, err)
+			}
+			n.References = references
+			v.Notes = append(v.Notes, n)
+		}
+		for _, span := range row.Spans {
+			v.Spans = append(v.Spans, sources.Span{Style: span.Style, Start: span.Start, End: span.End})
+		}
+		v.Words = readWords(v, links[row.ID])
+		v.Tokens = readTokens(v, row.Tokens)
+		verses[i] = v
+	}
+	return verses, nil
+}
+
+/**
+ * readTargets parses the targets of a note, stored as a list of OSIS
+ * references ("Gen.1.1-Gen.1.3 Jude"). A chapter of a single chapter book
+ * ("Jude.1") stays a chapter, where a typed "Jude 1" would be a verse.
+ */
+func readTargets(targets string) ([]bible.BibleReference, error) {
+	var references []bible.BibleReference
+	for _, target := range strings.Fields(targets) {
+		ref, err := bible.NormalizeBibleReference(target)
+		if err != nil {
+			return nil, err
+		}
+		if ref.Book.SingleChapter() && strings.Count(target, ".") == 1 && !strings.Contains(target, "-") {
+			ref = bible.BibleReference{Book: ref.Book, StartChapter: 1, EndChapter: 1, Granularity: bible.GRANULARITY_CHAPTER}
+		}
+		references = append(references, ref)
+	}
+	return references, nil
+}
+
+// readWords turns the tagged words of a verse back into the words of the
+// source: one per "\w" span, or per word where there is none.
+func readWords(v sources.Verse, links []taggedLink) []sources.Word {
+	tokens := splitWords(v.Text)
+	var words []sources.Word
+	for _, link := range links {
+		if int(link.Position) >= len(tokens) {
+			continue
+		}
+		token := tokens[link.Position]
+		word := sources.Word{Start: token.start, End: token.end, Strong: link.Strong, Lemma: link.Lemma, Morph: link.Morph}
+		for _, span := range v.Spans {
+			if span.Style == "w" && span.Start <= token.start && token.end <= span.End {
+				word.Start, word.End = span.Start, span.End
+				break
+			}
+		}
+		if n := len(words); n > 0 && words[n-1].Start == word.Start && words[n-1].End == word.End {
+			// Another word of the same span
+			continue
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// readTokens turns the interlinear tokens of a verse back into ranges of its
+// text.
+func readTokens(v sources.Verse, rows []dbmodels.InterlinearToken) []sources.Token {
+	words := splitWords(v.Text)
+	offset := func(position uint) int {
+		if int(position) < len(words) {
+			return words[position].start
+		}
+		return len(v.Text)
+	}
+
+	var tokens []sources.Token
+	for _, row := range rows {
+		token := sources.Token{
+			Language:        row.Language,
+			Text:            row.Text,
+			Transliteration: row.Transliteration,
+			Lemma:           row.Lemma,
+			Strong:          row.Strong,
+			Morph:           row.Morph,
+			MorphLabel:      row.MorphLabel,
+			Gloss:           row.Gloss,
+		}
+		token.Start, token.End = offset(row.WordStart), offset(row.WordStart)
+		if row.WordEnd > row.WordStart && int(row.WordEnd) <= len(words) {
+			token.End = words[row.WordEnd-1].end
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
@@ -0,0 +1,444 @@
+package loader
+
+import (
+	"fmt"
+
+	"bibleapp.server/internal/dbmodels"
+	"bibleapp.server/internal/models"
+	bible "bibleapp.server/pkg/bible_parser"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// storedVerse is the part of a stored verse needed to compare it with the
+// source.
+type storedVerse struct {
+	ID     uint
+	Number uint
+	Text   string
+	Hash   string
+}
+
+// attachments are the rows hanging off a set of verses.
+type attachments struct {
+	headings []dbmodels.Heading
+	notes    []dbmodels.Note
+	spans    []dbmodels.Span
+	links    []dbmodels.VerseWord
+	tokens   []dbmodels.InterlinearToken
+}
+
+/**
+ * store brings the chapters of a batch up to date. Chapters whose hash has
+ * not changed are skipped; for the others the verses are matched with the
+ * stored ones by number and only those added, changed or removed are
+ * written. Changed verses keep their row, so that anything pointing at them
+ * stays valid, and get their headings, notes, spans and words rewritten.
+ */
+func (l *loader) store(tx *gorm.DB, batch []preparedChapter) error {
+	for _, ch := range batch {
+		l.progress.Verses += len(ch.verses)
+
+		stored := l.chapters[ch.book][ch.chapter]
+		if stored != nil {
+			stored.seen = true
+			if stored.hash == ch.hash {
+				l.progress.Unchanged += len(ch.verses)
+				continue
+			}
+		}
+
+		var existing []storedVerse
+		if stored != nil {
+			err := tx.Model(&dbmodels.Verse{}).Where("chapter_id = ?", stored.id).
+				Select("id", "number", "text", "hash").Order("id").Scan(&existing).Error
+			if err != nil {
+				return err
+			}
+		}
+		if err := l.storeChapter(tx, ch, existing); err != nil {
+			return fmt.Errorf("%s %d: %w", ch.book, ch.chapter, err)
+		}
+	}
+	return nil
+}
+
+// storeChapter writes the differences between the verses of a chapter and
+// the existing ones.
+func (l *loader) storeChapter(tx *gorm.DB, ch preparedChapter, existing []storedVerse) error {
+	// A number repeated in the source is matched with the next stored verse
+	// with that number, if any.
+	byNumber := map[uint][]storedVerse{}
+	for _, v := range existing {
+		byNumber[v.Number] = append(byNumber[v.Number], v)
+	}
+
+	var added, changed []preparedVerse
+	var changedIDs []uint
+	for _, p := range ch.verses {
+		matches := byNumber[p.verse.Number]
+		if len(matches) == 0 {
+			added = append(added, p)
+			l.report(Change{Kind: CHANGE_ADDED, Book: ch.book, Chapter: ch.chapter, Verse: p.verse.Number, New: p.verse.Text})
+			continue
+		}
+		old := matches[0]
+		byNumber[p.verse.Number] = matches[1:]
+		if old.Hash != p.hash {
+			changed = append(changed, p)
+			changedIDs = append(changedIDs, old.ID)
+			l.report(Change{Kind: CHANGE_CHANGED, Book: ch.book, Chapter: ch.chapter, Verse: p.verse.Number, Old: old.Text, New: p.verse.Text})
+		}
+	}
+	var removedIDs []uint
+	for _, v := range existing {
+		for _, left := range byNumber[v.Number] {
+			if left.ID == v.ID {
+				removedIDs = append(removedIDs, v.ID)
+				l.report(Change{Kind: CHANGE_REMOVED, Book: ch.book, Chapter: ch.chapter, Verse: v.Number, Old: v.Text})
+			}
+		}
+	}
+	l.progress.Unchanged += len(ch.verses) - len(added) - len(changed)
+	l.progress.Added += len(added)
+	l.progress.Changed += len(changed)
+	l.progress.Removed += len(removedIDs)
+	if l.opts.DryRun {
+		return nil
+	}
+
+	chapterID, err := l.chapterID(tx, ch.book, ch.chapter)
+	if err != nil {
+		return err
+	}
+	if err := l.createWords(tx, added); err != nil {
+		return err
+	}
+	if err := l.createWords(tx, changed); err != nil {
+		return err
+	}
+
+	// Added verses get new rows, changed ones are updated in place
+	verses := make([]dbmodels.Verse, len(added))
+	for i, p := range added {
+		verses[i] = verseRow(p, chapterID)
+	}
+	if err := createAll(tx, verses); err != nil {
+		return err
+	}
+	for i, p := range changed {
+		row := verseRow(p, chapterID)
+		err := tx.Model(&dbmodels.Verse{}).Where("id = ?", changedIDs[i]).
+			Select("vid", "number", "end_number", "text", "paragraph", "hash").Updates(&row).Error
+		if err != nil {
+			return err
+		}
+	}
+	if err := deleteVerses(tx, changedIDs, false); err != nil {
+		return err
+	}
+	if err := deleteVerses(tx, removedIDs, true); err != nil {
+		return err
+	}
+
+	var rows attachments
+	for i, p := range added {
+		l.attach(&rows, p, verses[i].ID)
+	}
+	for i, p := range changed {
+		l.attach(&rows, p, changedIDs[i])
+	}
+	if err := rows.create(tx); err != nil {
+		return err
+	}
+	l.progress.Links += len(rows.links)
+
+	// Stored last, so that a chapter interrupted halfway is compared again
+	l.chapters[ch.book][ch.chapter].hash = ch.hash
+	return tx.Model(&dbmodels.Chapter{}).Where("id = ?", chapterID).Update("hash", ch.hash).Error
+}
+
+/**
+ * removeUnseen removes the chapters that were not in the source, with their
+ * verses, and the books left without chapters. Books the source did not
+ * have at all are only removed with Options.Prune.
+ */
+func (l *loader) removeUnseen(tx *gorm.DB) error {
+	for book, chapters := range l.chapters {
+		if !l.opts.Prune && !anySeen(chapters) {
+			continue
+		}
+		left := 0
+		for number, stored := range chapters {
+			if stored.seen {
+				left++
+				continue
+			}
+
+			var existing []storedVerse
+			err := tx.Model(&dbmodels.Verse{}).Where("chapter_id = ?", stored.id).
+				Select("id", "number", "text").Order("id").Scan(&existing).Error
+			if err != nil {
+				return err
+			}
+			ids := make([]uint, len(existing))
+			for i, v := range existing {
+				ids[i] = v.ID
+				l.report(Change{Kind: CHANGE_REMOVED, Book: book, Chapter: number, Verse: v.Number, Old: v.Text})
+			}
+			l.progress.Removed += len(existing)
+			if l.opts.DryRun {
+				continue
+			}
+
+			if err := deleteVerses(tx, ids, true); err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Delete(&dbmodels.Chapter{}, stored.id).Error; err != nil {
+				return err
+			}
+			delete(chapters, number)
+		}
+		if left == 0 && !l.opts.DryRun {
+			if err := tx.Unscoped().Delete(&dbmodels.Book{}, l.bookIDs[book]).Error; err != nil {
+				return err
+			}
+			delete(l.bookIDs, book)
+			delete(l.chapters, book)
+		}
+	}
+	return nil
+}
+
+// anySeen reports whether the source had any of the chapters.
+func anySeen(chapters map[uint]*storedChapter) bool {
+	for _, stored := range chapters {
+		if stored.seen {
+			return true
+		}
+	}
+	return false
+}
+
+// report passes a change on to Options.Changes.
+func (l *loader) report(change Change) {
+	if l.opts.Changes != nil {
+		l.opts.Changes(change)
+	}
+}
+
+/**
+ * deleteVerses deletes the headings, notes, spans, word links, lexicon links
+ * and interlinear tokens of verses, and the verses themselves when verses is
+ * set. Rows are deleted for good: soft deleted links would still hold the
+ * primary keys of the new ones.
+ */
+func deleteVerses(tx *gorm.DB, ids []uint, verses bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	tables := []interface{}{&dbmodels.Heading{}, &dbmodels.Note{}, &dbmodels.Span{}, &dbmodels.VerseWord{}, &dbmodels.VerseWordEntry{}, &dbmodels.InterlinearToken{}}
+	for _, model := range tables {
+		if err := tx.Unscoped().Where("verse_id IN ?", ids).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	if !verses {
+		return nil
+	}
+	return tx.Unscoped().Delete(&dbmodels.Verse{}, ids).Error
+}
+
+// verseRow returns the row of a verse.
+func verseRow(p preparedVerse, chapterID uint) dbmodels.Verse {
+	v := p.verse
+	return dbmodels.Verse{
+		VID:       uint(bible.NewVerseID(v.Book, v.Chapter, v.Number)),
+		Number:    v.Number,
+		EndNumber: v.EndNumber,
+		Text:      v.Text,
+		Paragraph: v.Paragraph,
+		Hash:      p.hash,
+		ChapterID: chapterID,
+	}
+}
+
+// attach adds the headings, notes, spans, word links and interlinear tokens
+// of a verse to rows.
+func (l *loader) attach(rows *attachments, p preparedVerse, verseID uint) {
+	v := p.verse
+	for position, heading := range v.Headings {
+		rows.headings = append(rows.headings, dbmodels.Heading{
+			Heading: models.Heading{
+				VerseID:  verseID,
+				Style:    heading.Style,
+				Text:     heading.Text,
+				Position: uint(position),
+			},
+		})
+	}
+	for _, note := range v.Notes {
+		rows.notes = append(rows.notes, dbmodels.Note{
+			Note: models.Note{
+				VerseID: verseID,
+				Style:   note.Style,
+				Caller:  note.Caller,
+				Offset:  note.Offset,
+				Text:    note.Text,
+				Targets: bible.FormatReferenceList(note.References, bible.STYLE_OSIS),
+			},
+		})
+	}
+	for _, span := range v.Spans {
+		rows.spans = append(rows.spans, dbmodels.Span{
+			Span: models.Span{
+				VerseID: verseID,
+				Style:   span.Style,
+				Start:   span.Start,
+				End:     span.End,
+			},
+		})
+	}
+
+	// Link the verse to each of its words, along with the Strong's numbers
+	// the source tagged them with.
+	for position, token := range p.words {
+		link := dbmodels.VerseWord{
+			VerseWord: models.VerseWord{
+				VerseID:  int(verseID),
+				WordID:   int(l.wordIDs[token.word]),
+				Position: uint(position),
+			},
+		}
+		for _, w := range v.Words {
+			if w.Start < token.end && token.start < w.End {
+				link.Strong, link.Lemma, link.Morph = w.Strong, w.Lemma, w.Morph
+			}
+		}
+		rows.links = append(rows.links, link)
+	}
+
+	// Interlinear tokens point at the words their glosses cover
+	for position, token := range v.Tokens {
+		start, end := len(p.words), len(p.words)
+		for i, w := range p.words {
+			if w.end > token.Start && start == len(p.words) {
+				start = i
+			}
+			if w.start < token.End {
+				end = i + 1
+			}
+		}
+		if end < start || token.End <= token.Start {
+			end = start
+		}
+		rows.tokens = append(rows.tokens, dbmodels.InterlinearToken{
+			InterlinearToken: models.InterlinearToken{
+				VerseID:         verseID,
+				Position:        uint(position),
+				Language:        token.Language,
+				Text:            token.Text,
+				Transliteration: token.Transliteration,
+				Lemma:           token.Lemma,
+				Strong:          token.Strong,
+				Morph:           token.Morph,
+				MorphLabel:      token.MorphLabel,
+				Gloss:           token.Gloss,
+				WordStart:       uint(start),
+				WordEnd:         uint(end),
+			},
+		})
+	}
+}
+
+// create inserts the rows.
+func (rows *attachments) create(tx *gorm.DB) error {
+	if err := createAll(tx, rows.headings); err != nil {
+		return err
+	}
+	if err := createAll(tx, rows.notes); err != nil {
+		return err
+	}
+	if err := createAll(tx, rows.spans); err != nil {
+		return err
+	}
+	if err := createAll(tx, rows.links); err != nil {
+		return err
+	}
+	return createAll(tx, rows.tokens)
+}
+
+// createWords stores the words of the verses that are not in the cache yet.
+func (l *loader) createWords(tx *gorm.DB, batch []preparedVerse) error {
+	var words []dbmodels.Word
+	seen := map[string]bool{}
+	for _, p := range batch {
+		for _, token := range p.words {
+			if _, ok := l.wordIDs[token.word]; !ok && !seen[token.word] {
+				seen[token.word] = true
+				words = append(words, dbmodels.Word{Word: models.Word{Word: token.word}})
+			}
+		}
+	}
+	if len(words) == 0 {
+		return nil
+	}
+
+	if err := tx.CreateInBatches(&words, rowsPerInsert).Error; err != nil {
+		return err
+	}
+	for _, word := range words {
+		l.wordIDs[word.Word.Word] = word.ID
+	}
+	l.progress.NewWords += len(words)
+	return nil
+}
+
+// chapterID returns the ID of a chapter of the bible, storing the chapter
+// and its book the first time they are seen.
+func (l *loader) chapterID(tx *gorm.DB, book bible.Book, chapter uint) (uint, error) {
+	//============= BOOK ==================
+	bookID, ok := l.bookIDs[book]
+	if !ok {
+		bookDB := dbmodels.Book{
+			Book: models.Book{
+				Name:      l.names.Name(book),
+				ShortName: l.names.Abbreviation(book),
+				Code:      book.Code(),
+				Position:  uint(book) + 1,
+				BibleID:   l.bibleID,
+			},
+		}
+		if err := tx.Omit(clause.Associations).Create(&bookDB).Error; err != nil {
+			return 0, fmt.Errorf("creating %s: %w", book, err)
+		}
+		bookID = bookDB.ID
+		l.bookIDs[book] = bookID
+		l.chapters[book] = map[uint]*storedChapter{}
+	}
+
+	//============= CHAPTER ==================
+	stored, ok := l.chapters[book][chapter]
+	if !ok {
+		chapterDB := dbmodels.Chapter{
+			Chapter: models.Chapter{
+				Number: chapter,
+				BookID: bookID,
+			},
+		}
+		if err := tx.Omit(clause.Associations).Create(&chapterDB).Error; err != nil {
+			return 0, fmt.Errorf("creating %s %d: %w", book, chapter, err)
+		}
+		stored = &storedChapter{id: chapterDB.ID, seen: true}
+		l.chapters[book][chapter] = stored
+	}
+	return stored.id, nil
+}
+
+// createAll inserts rows with multi-row inserts.
+func createAll[T any](tx *gorm.DB, rows []T) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.Omit(clause.Associations).CreateInBatches(&rows, rowsPerInsert).Error
+}
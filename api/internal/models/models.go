@@ -50,6 +50,49 @@ type KeyGenreEnglish struct {
 	Name			string
 }
 
+type Bible struct {
+	Name			string
+	Abbreviation	string `gorm:"uniqueIndex"`
+	Language		string
+	Description		string
+}
+
+type Book struct {
+	Name			string
+	ShortName		string
+	Code			string //USFM book code
+	Position		uint
+	BibleID			uint
+}
+
+type Chapter struct {
+	Number			uint
+	BookID			uint
+	Hash			string //Of the chapter's verses as last imported
+}
+
+type Heading struct {
+	VerseID			uint
+	Style			string //USFM marker, e.g. s1
+	Text			string
+	Position		uint //Order of the headings before the verse
+}
+
+type Note struct {
+	VerseID			uint
+	Style			string //f (footnote) or x (cross reference)
+	Caller			string
+	Offset			int //Position in the verse text, in bytes
+	Text			string
+	Targets			string //OSIS references, space separated
+}
+
+type Span struct {
+	VerseID			uint
+	Style			string //USFM character marker, e.g. wj
+	Start			int
+	End				int
+}
 
 type Word struct {
 	Word 			string
@@ -59,6 +102,48 @@ type VerseWord struct {
 	VerseID  		int `gorm:"primaryKey"`
 	WordID 			int `gorm:"primaryKey"`
 	Position		uint `gorm:"primaryKey"` //Position in the sentence
+	Strong			string //Strong's numbers of the word, space separated
+	Lemma			string
+	Morph			string
 	CreatedAt time.Time
 	DeletedAt gorm.DeletedAt
 }
+
+type Lexicon struct {
+	Name			string
+	Abbreviation	string `gorm:"uniqueIndex"`
+	Language		string //Of the lemmas, e.g. hbo or grc
+	Source			string //Where the entries were imported from
+}
+
+type LexiconEntry struct {
+	LexiconID		uint `gorm:"uniqueIndex:idx_lexicon_entry"`
+	Strong			string `gorm:"uniqueIndex:idx_lexicon_entry;index"` //Strong's number without padding, e.g. H430
+	Lemma			string
+	Transliteration	string
+	Pronunciation	string
+	Derivation		string
+	Definition		string //Strong's own definition
+	KJVDefinition	string //The renderings of the word in the KJV
+}
+
+type VerseWordEntry struct {
+	VerseID			uint `gorm:"uniqueIndex:idx_verse_word_entry"`
+	Position		uint `gorm:"uniqueIndex:idx_verse_word_entry"` //Of the word in the verse, as in VerseWord
+	LexiconEntryID	uint `gorm:"uniqueIndex:idx_verse_word_entry;index"`
+}
+
+type InterlinearToken struct {
+	VerseID			uint `gorm:"index"`
+	Position		uint //In the original text
+	Language		string //hbo, arc or grc
+	Text			string
+	Transliteration	string
+	Lemma			string
+	Strong			string
+	Morph			string
+	MorphLabel		string //Description of Morph, when the source gives one
+	Gloss			string
+	WordStart		uint //Position of the first VerseWord rendering the token
+	WordEnd			uint //Position after the last one, WordStart when untranslated
+}
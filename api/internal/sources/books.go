@@ -0,0 +1,86 @@
+package sources
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/**
+ * bookReader reads sources stored as one file per book, as USFM and USX
+ * are: either a single file or every matching file of a directory in name
+ * order (Paratext names them "01GENxxx.SFM", ...). Each file is parsed
+ * whole when the previous one runs out of verses.
+ */
+type bookReader struct {
+	files  []string
+	verses []Verse
+	parse  func(content []byte) ([]Verse, error)
+	meta   Metadata
+}
+
+// sourceFiles returns path itself, or the files in the directory path with
+// one of the given extensions, sorted by name.
+func sourceFiles(path string, extensions ...string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		for _, e := range extensions {
+			if !entry.IsDir() && ext == e {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no %s files in %s", strings.Join(extensions, "/"), path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (r *bookReader) Metadata() Metadata {
+	return r.meta
+}
+
+func (r *bookReader) Next() (Verse, error) {
+	for len(r.verses) == 0 {
+		if len(r.files) == 0 {
+			return Verse{}, io.EOF
+		}
+		file := r.files[0]
+		r.files = r.files[1:]
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return Verse{}, err
+		}
+		verses, err := r.parse(content)
+		if err != nil {
+			return Verse{}, fmt.Errorf("%s: %w", file, err)
+		}
+		r.verses = verses
+	}
+
+	v := r.verses[0]
+	r.verses = r.verses[1:]
+	return v, nil
+}
+
+func (r *bookReader) Close() error {
+	return nil
+}
@@ -0,0 +1,317 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	bible "bibleapp.server/pkg/bible_parser"
+)
+
+func init() {
+	Register("bsb-interlinear", OpenBSBInterlinear)
+}
+
+/**
+ * OpenBSBInterlinear opens the Berean Standard Bible interlinear as prepared
+ * by web-admin (versions/interlinear/bsb): a directory with a JSON file per
+ * book ("GEN.json") and the version.json describing the version. Books are
+ * read in canonical order.
+ */
+func OpenBSBInterlinear(path string) (SourceReader, error) {
+	files, err := sourceFiles(path, ".json")
+	if err != nil {
+		return nil, err
+	}
+
+	var meta Metadata
+	books := map[string]bible.Book{}
+	var bookFiles []string
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if name == "version" {
+			if meta, err = bsbMetadata(file); err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			continue
+		}
+		book, ok := bible.BookFromCode(name)
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown book code %q", file, name)
+		}
+		books[file] = book
+		bookFiles = append(bookFiles, file)
+	}
+	sort.SliceStable(bookFiles, func(i, j int) bool { return books[bookFiles[i]] < books[bookFiles[j]] })
+
+	return &bookReader{files: bookFiles, meta: meta, parse: ParseBSBInterlinear}, nil
+}
+
+// bsbMetadata reads the version.json written by web-admin.
+func bsbMetadata(path string) (Metadata, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Metadata{}, err
+	}
+	var version struct {
+		Abbreviation string `json:"abbreviation"`
+		Title        string `json:"title"`
+		Language     struct {
+			ISO6391 string `json:"iso_639_1"`
+		} `json:"language"`
+	}
+	if err := json.Unmarshal(content, &version); err != nil {
+		return Metadata{}, err
+	}
+	return Metadata{
+		Abbreviation: version.Abbreviation,
+		Name:         version.Title,
+		Language:     version.Language.ISO6391,
+	}, nil
+}
+
+// bsbValue is a spreadsheet cell, which web-admin writes as a string, a
+// number or null.
+type bsbValue string
+
+func (v *bsbValue) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*v = ""
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*v = bsbValue(s)
+		return nil
+	}
+	*v = bsbValue(data)
+	return nil
+}
+
+func (v bsbValue) String() string {
+	return strings.TrimSpace(string(v))
+}
+
+// bsbEntry is a row of the BSB tables: a word of the original text along
+// with the English rendering it in the BSB.
+type bsbEntry struct {
+	Language  bsbValue `json:"language"`
+	HebSort   bsbValue `json:"heb_sort"`
+	GrkSort   bsbValue `json:"grk_sort"`
+	WLC       bsbValue `json:"wlc"`
+	Translit  bsbValue `json:"translit"`
+	TOS       bsbValue `json:"tos"`
+	TOSLabel  bsbValue `json:"tos_label"`
+	Strongs   bsbValue `json:"strongs"`
+	Heading   bsbValue `json:"heading"`
+	XRef      bsbValue `json:"xref"`
+	Text      bsbValue `json:"text"`
+	Footnotes bsbValue `json:"footnotes"`
+}
+
+// Languages of the BSB tables and their codes.
+var bsbLanguages = map[string]string{
+	"hebrew":  "hbo",
+	"aramaic": "arc",
+	"greek":   "grc",
+}
+
+var (
+	bsbGlossPrefix = regexp.MustCompile(`^\. \.( \.)*`)
+	bsbGlossSuffix = regexp.MustCompile(` \. \.( \.)*$`)
+	bsbTags        = regexp.MustCompile(`<[^>]*>`)
+)
+
+/**
+ * ParseBSBInterlinear parses a book of the prepared BSB interlinear: the
+ * rows of each verse ("GEN.001.001") in the order of the English text,
+ * along with that text. Rows become tokens, aligned with the words of the
+ * text their glosses render and sorted in the order of the original text
+ * when the rows carry it; the words of the text are tagged with the Strong's
+ * numbers of the tokens they render. Headings become "s1" headings, the
+ * passages they parallel "r" headings, and footnotes notes after the gloss
+ * they annotate.
+ */
+func ParseBSBInterlinear(content []byte) ([]Verse, error) {
+	var book map[string]struct {
+		Markup []bsbEntry `json:"markup"`
+		Text   string     `json:"text"`
+	}
+	if err := json.Unmarshal(content, &book); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(book))
+	for key := range book {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var verses []Verse
+	for _, key := range keys {
+		parts := strings.Split(key, ".")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid verse key %q", key)
+		}
+		b, ok := bible.BookFromCode(parts[0])
+		chapter, err1 := strconv.Atoi(parts[1])
+		number, err2 := strconv.Atoi(parts[2])
+		if !ok || err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("invalid verse key %q", key)
+		}
+
+		entry := book[key]
+		v := Verse{Book: b, Chapter: uint(chapter), Number: uint(number), Text: entry.Text}
+		words := textWords(v.Text)
+		cursor := 0
+		order := make([]int, len(entry.Markup))
+		for i, row := range entry.Markup {
+			if heading := row.Heading.String(); heading != "" {
+				v.Headings = append(v.Headings, Heading{Style: "s1", Text: heading})
+			}
+			if parallel := row.XRef.String(); parallel != "" {
+				v.Headings = append(v.Headings, Heading{Style: "r", Text: parallel})
+			}
+
+			language := strings.ToLower(row.Language.String())
+			token := Token{
+				Language:        bsbLanguages[language],
+				Text:            row.WLC.String(),
+				Transliteration: row.Translit.String(),
+				Morph:           row.TOS.String(),
+				MorphLabel:      row.TOSLabel.String(),
+				Gloss:           bsbGloss(row.Text.String()),
+			}
+			if token.Language == "" {
+				token.Language = language
+			}
+			if strongs := row.Strongs.String(); strongs != "" {
+				prefix := "H"
+				if token.Language == "grc" {
+					prefix = "G"
+				}
+				token.Strong = prefix + strongs
+			}
+			token.Start, token.End, cursor = alignGloss(words, textWords(token.Gloss), cursor, len(v.Text))
+			v.Tokens = append(v.Tokens, token)
+			if token.End > token.Start && token.Strong != "" {
+				// The English words are tagged with what they render
+				v.Words = append(v.Words, Word{Start: token.Start, End: token.End, Strong: token.Strong})
+			}
+
+			if footnote := row.Footnotes.String(); footnote != "" {
+				v.Notes = append(v.Notes, Note{
+					Style:  "f",
+					Offset: token.End,
+					Text:   strings.TrimSpace(html.UnescapeString(bsbTags.ReplaceAllString(footnote, ""))),
+				})
+			}
+
+			order[i] = i
+			sortKey := row.HebSort
+			if token.Language == "grc" {
+				sortKey = row.GrkSort
+			}
+			if n, err := strconv.Atoi(sortKey.String()); err == nil {
+				order[i] = n
+			}
+		}
+
+		// The rows follow the English text, the tokens the original one
+		positions := make([]int, len(v.Tokens))
+		for i := range positions {
+			positions[i] = i
+		}
+		sort.SliceStable(positions, func(i, j int) bool { return order[positions[i]] < order[positions[j]] })
+		tokens := make([]Token, len(v.Tokens))
+		for i, position := range positions {
+			tokens[i] = v.Tokens[position]
+		}
+		v.Tokens = tokens
+		verses = append(verses, v)
+	}
+	return verses, nil
+}
+
+// bsbGloss cleans a gloss up as web-admin does when it builds the verse
+// text: "-" marks a word left untranslated, dots an elision.
+func bsbGloss(gloss string) string {
+	if strings.HasPrefix(gloss, "-") {
+		gloss = strings.TrimSpace(gloss[1:])
+	}
+	gloss = bsbGlossPrefix.ReplaceAllString(gloss, "")
+	gloss = bsbGlossSuffix.ReplaceAllString(gloss, "")
+	gloss = strings.TrimSuffix(gloss, " -")
+	return strings.TrimSpace(gloss)
+}
+
+// textWord is a word of a text and where it is, as the loader splits words.
+type textWord struct {
+	word       string
+	start, end int
+}
+
+// textWords splits text into words, dropping the punctuation around them.
+func textWords(text string) []textWord {
+	var words []textWord
+	start := -1
+	for i, r := range text + " " {
+		if !unicode.IsSpace(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start < 0 {
+			continue
+		}
+		word := strings.TrimFunc(text[start:i], unicode.IsPunct)
+		if word != "" {
+			words = append(words, textWord{word: word, start: start, end: i})
+		}
+		start = -1
+	}
+	return words
+}
+
+// How many words of the text a gloss may be looked for past the cursor.
+const glossLookahead = 3
+
+/**
+ * alignGloss finds the words of a gloss among the words of the text, from
+ * the word at cursor on, and returns the range of the text they cover and
+ * the cursor after them. A gloss that cannot be found, or has no words,
+ * gets an empty range at the cursor.
+ */
+func alignGloss(words []textWord, gloss []textWord, cursor int, textLen int) (int, int, int) {
+	at := textLen
+	if cursor < len(words) {
+		at = words[cursor].start
+	}
+	if len(gloss) == 0 {
+		return at, at, cursor
+	}
+	for first := cursor; first <= cursor+glossLookahead && first+len(gloss) <= len(words); first++ {
+		matched := true
+		for k, w := range gloss {
+			if !strings.EqualFold(words[first+k].word, w.word) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			last := first + len(gloss) - 1
+			return words[first].start, words[last].end, last + 1
+		}
+	}
+	return at, at, cursor
+}
@@ -0,0 +1,449 @@
+package sources
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	bible "bibleapp.server/pkg/bible_parser"
+)
+
+func init() {
+	Register("osis", OpenOSIS)
+}
+
+// OpenOSIS opens an OSIS document or a directory of them. The version is
+// described by the <header> of the first document.
+func OpenOSIS(path string) (SourceReader, error) {
+	files, err := sourceFiles(path, ".osis", ".xml")
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(files[0])
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	meta, err := osisMetadata(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", files[0], err)
+	}
+
+	return &bookReader{files: files, meta: meta, parse: func(content []byte) ([]Verse, error) {
+		return ParseOSIS(bytes.NewReader(content))
+	}}, nil
+}
+
+// osisMetadata reads the version description from an OSIS header.
+func osisMetadata(r io.Reader) (Metadata, error) {
+	var meta Metadata
+	decoder := xml.NewDecoder(r)
+	var path []string
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return meta, nil
+		}
+		if err != nil {
+			return meta, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			path = append(path, t.Name.Local)
+			switch t.Name.Local {
+			case "osisText":
+				meta.Abbreviation = attr(t, "osisIDWork")
+				meta.Language = attr(t, "lang")
+			case "div":
+				// The header is over
+				return meta, nil
+			}
+		case xml.EndElement:
+			path = path[:len(path)-1]
+			if t.Name.Local == "header" {
+				return meta, nil
+			}
+		case xml.CharData:
+			// Only the first <work> describes this document
+			if len(path) < 2 || path[len(path)-2] != "work" || meta.Name != "" && path[len(path)-1] == "title" {
+				continue
+			}
+			text := strings.TrimSpace(string(t))
+			switch path[len(path)-1] {
+			case "title":
+				meta.Name = text
+			case "description":
+				if meta.Description == "" {
+					meta.Description = text
+				}
+			case "refSystem":
+				// "Bible.KJV"
+				meta.Versification = strings.TrimPrefix(text, "Bible.")
+			}
+		}
+	}
+}
+
+// OSIS character markup and the USFM character styles they become.
+var osisHighlights = map[string]string{
+	"italic":     "it",
+	"bold":       "bd",
+	"small-caps": "sc",
+	"super":      "sup",
+	"underline":  "em",
+}
+
+/**
+ * ParseOSIS reads the verses of an OSIS document. Both verse encodings are
+ * accepted: containers (<verse osisID="Gen.1.1">...</verse>) and milestones
+ * (<verse sID="Gen.1.1" osisID="Gen.1.1"/>...<verse eID="Gen.1.1"/>). Like
+ * USX, the elements are fed to the USFM parser as the markers they stand
+ * for:
+ *
+ *   <title>                   \s (\d for psalm titles)
+ *   <note>                    \f, or \x for type="crossReference"
+ *   <divineName>              \nd
+ *   <transChange>             \add
+ *   <q who="Jesus">           \wj
+ *   <p>, <l level="2">        \p, \q2
+ *   <w lemma="strong:H0430">  \w with the Strong's number
+ *
+ * Book and chapter titles, colophons and the header are skipped.
+ */
+func ParseOSIS(r io.Reader) ([]Verse, error) {
+	p := &usfmParser{}
+	return p.parseXML(xml.NewDecoder(r), p.osisElement)
+}
+
+/**
+ * parseXML feeds an XML document to the parser. element is called for the
+ * start of every element and returns what to do at the element's end, or
+ * whether to skip the element and all its content.
+ */
+func (p *usfmParser) parseXML(decoder *xml.Decoder, element func(e xml.StartElement) (func() error, bool, error)) ([]Verse, error) {
+	// For each open element, what its end does
+	var closing []func() error
+	skipDepth := 0
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if skipDepth > 0 {
+				skipDepth++
+				continue
+			}
+			end, skip, err := element(t)
+			if err != nil {
+				return nil, err
+			}
+			if skip {
+				skipDepth = 1
+				continue
+			}
+			closing = append(closing, end)
+
+		case xml.EndElement:
+			if skipDepth > 0 {
+				skipDepth--
+				continue
+			}
+			if len(closing) == 0 {
+				continue
+			}
+			end := closing[len(closing)-1]
+			closing = closing[:len(closing)-1]
+			if end == nil {
+				continue
+			}
+			if err := end(); err != nil {
+				return nil, err
+			}
+
+		case xml.CharData:
+			if skipDepth > 0 {
+				continue
+			}
+			if err := p.token(usfmToken{text: string(t)}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	p.endVerse()
+	return p.verses, nil
+}
+
+/**
+ * osisElement feeds the start of an OSIS element to the parser, see
+ * parseXML.
+ */
+func (p *usfmParser) osisElement(e xml.StartElement) (func() error, bool, error) {
+	// Markup written either as a container or as sID/eID milestones
+	openOrMilestone := func(marker string) (func() error, bool, error) {
+		if attr(e, "eID") != "" {
+			return nil, false, p.token(usfmToken{marker: marker, closing: true})
+		}
+		if attr(e, "sID") != "" {
+			return nil, false, p.token(usfmToken{marker: marker})
+		}
+		return p.openStyle(marker)
+	}
+
+	switch e.Name.Local {
+	case "header":
+		return nil, true, nil
+
+	case "div":
+		switch attr(e, "type") {
+		case "book":
+			return nil, false, p.osisBook(attr(e, "osisID"))
+		case "colophon", "introduction", "preface", "titlePage", "glossary":
+			return nil, true, nil
+		}
+
+	case "chapter":
+		if attr(e, "eID") != "" {
+			return nil, false, nil
+		}
+		return nil, false, p.osisVerse(attr(e, "osisID"), false)
+
+	case "verse":
+		if attr(e, "eID") != "" {
+			return nil, false, nil
+		}
+		return nil, false, p.osisVerse(attr(e, "osisID"), true)
+
+	case "title":
+		if p.note != nil {
+			return nil, false, nil
+		}
+		marker := "s"
+		switch attr(e, "type") {
+		case "main", "chapter", "runningHead", "x-preverse", "x-gen":
+			return nil, true, nil
+		case "psalm":
+			marker = "d"
+		}
+		if err := p.token(usfmToken{marker: marker}); err != nil {
+			return nil, false, err
+		}
+		return func() error {
+			p.heading = nil
+			return nil
+		}, false, nil
+
+	case "note":
+		marker, caller := "f", "+"
+		if attr(e, "type") == "crossReference" {
+			marker, caller = "x", "-"
+		}
+		if n := attr(e, "n"); n != "" {
+			caller = n
+		}
+		if err := p.token(usfmToken{marker: marker}); err != nil {
+			return nil, false, err
+		}
+		if err := p.token(usfmToken{text: caller + " "}); err != nil {
+			return nil, false, err
+		}
+		return p.closeStyle(marker), false, nil
+
+	case "reference":
+		if p.note != nil && attr(e, "osisRef") != "" {
+			if p.targets.Len() > 0 {
+				p.targets.WriteString("; ")
+			}
+			p.targets.WriteString(attr(e, "osisRef"))
+		}
+
+	case "divineName":
+		return p.openStyle("nd")
+	case "transChange":
+		return p.openStyle("add")
+	case "foreign":
+		return p.openStyle("tl")
+	case "hi":
+		if marker, ok := osisHighlights[attr(e, "type")]; ok {
+			return p.openStyle(marker)
+		}
+	case "q":
+		if attr(e, "who") == "Jesus" {
+			return openOrMilestone("wj")
+		}
+
+	case "p":
+		if attr(e, "eID") == "" {
+			return nil, false, p.token(usfmToken{marker: "p"})
+		}
+	case "milestone":
+		if attr(e, "type") == "x-p" {
+			return nil, false, p.token(usfmToken{marker: "p"})
+		}
+	case "l":
+		if attr(e, "eID") == "" {
+			level := attr(e, "level")
+			if level == "" {
+				level = "1"
+			}
+			return nil, false, p.token(usfmToken{marker: "q" + level})
+		}
+
+	case "w":
+		return p.osisWord(e)
+	}
+	return nil, false, nil
+}
+
+// openStyle opens a USFM character style or note for the content of an
+// element.
+func (p *usfmParser) openStyle(marker string) (func() error, bool, error) {
+	err := p.token(usfmToken{marker: marker})
+	return p.closeStyle(marker), false, err
+}
+
+// closeStyle returns the end of an element closing marker.
+func (p *usfmParser) closeStyle(marker string) func() error {
+	return func() error {
+		return p.token(usfmToken{marker: marker, closing: true})
+	}
+}
+
+// osisBook starts the book with the given OSIS identifier.
+func (p *usfmParser) osisBook(osisID string) error {
+	book, ok := bible.BookFromOSIS(osisID)
+	if !ok {
+		return fmt.Errorf("unknown book %q", osisID)
+	}
+	if err := p.token(usfmToken{marker: "id"}); err != nil {
+		return err
+	}
+	return p.token(usfmToken{text: book.Code() + " "})
+}
+
+/**
+ * osisVerse starts the chapter or verse with the given osisID ("Gen.1" or
+ * "Gen.1.1"). Verses spanning several IDs ("Gen.1.1 Gen.1.2") become a
+ * bridge. The book and chapter are taken from the verse as well, so that
+ * documents without book or chapter elements are read correctly.
+ */
+func (p *usfmParser) osisVerse(osisID string, verse bool) error {
+	ids := strings.Fields(osisID)
+	if len(ids) == 0 {
+		return errors.New("chapter or verse without an osisID")
+	}
+	parts := strings.Split(ids[0], ".")
+	if len(parts) < 2 || (verse && len(parts) < 3) {
+		return fmt.Errorf("invalid osisID %q", osisID)
+	}
+
+	book, ok := bible.BookFromOSIS(parts[0])
+	if !ok {
+		return fmt.Errorf("unknown book %q", parts[0])
+	}
+	if !p.hasBook || book != p.book {
+		if err := p.osisBook(parts[0]); err != nil {
+			return err
+		}
+	}
+	if chapter := fmt.Sprint(p.chapter); !p.hasBook || p.intro || chapter != parts[1] {
+		if err := p.token(usfmToken{marker: "c"}); err != nil {
+			return err
+		}
+		if err := p.token(usfmToken{text: parts[1] + " "}); err != nil {
+			return err
+		}
+	}
+	if !verse {
+		return nil
+	}
+
+	number := parts[2]
+	if len(ids) > 1 {
+		last := strings.Split(ids[len(ids)-1], ".")
+		number += "-" + last[len(last)-1]
+	}
+	if err := p.token(usfmToken{marker: "v"}); err != nil {
+		return err
+	}
+	return p.token(usfmToken{text: number + " "})
+}
+
+// osisWord opens a <w> element, recording its Strong's numbers, lemmas and
+// morphology for the text it contains.
+func (p *usfmParser) osisWord(e xml.StartElement) (func() error, bool, error) {
+	var w Word
+	var strongs, lemmas []string
+	for _, lemma := range strings.Fields(attr(e, "lemma")) {
+		if strings.HasPrefix(lemma, "strong:") {
+			strongs = append(strongs, normalizeStrong(strings.TrimPrefix(lemma, "strong:")))
+		} else if _, value, ok := strings.Cut(lemma, ":"); ok {
+			lemmas = append(lemmas, value)
+		} else {
+			lemmas = append(lemmas, lemma)
+		}
+	}
+	w.Strong = strings.Join(strongs, " ")
+	w.Lemma = strings.Join(lemmas, " ")
+
+	var morphs []string
+	for _, morph := range strings.Fields(attr(e, "morph")) {
+		// "robinson:V-AAI-3S", "strongMorph:TH8804"
+		if _, value, ok := strings.Cut(morph, ":"); ok {
+			morph = value
+		}
+		morphs = append(morphs, morph)
+	}
+	w.Morph = strings.Join(morphs, " ")
+	return p.wordElement(w)
+}
+
+// wordElement records w for the text of the element being opened, up to
+// its end.
+func (p *usfmParser) wordElement(w Word) (func() error, bool, error) {
+	if p.note != nil || p.heading != nil || p.current == nil {
+		return nil, false, nil
+	}
+	start := p.text.Len()
+	return func() error {
+		if p.current == nil {
+			return nil
+		}
+		text := p.text.String()
+		end := len(strings.TrimRight(text, " "))
+		for start < end && text[start] == ' ' {
+			start++
+		}
+		if start < end && (w.Strong != "" || w.Lemma != "" || w.Morph != "") {
+			w.Start, w.End = start, end
+			p.current.Words = append(p.current.Words, w)
+		}
+		return nil
+	}, false, nil
+}
+
+// normalizeStrong drops the zero padding of a Strong's number: "H07225"
+// becomes "H7225".
+func normalizeStrong(strong string) string {
+	if len(strong) < 2 {
+		return strong
+	}
+	prefix, number := strong[:1], strings.TrimLeft(strong[1:], "0")
+	if number == "" {
+		number = "0"
+	}
+	return prefix + number
+}
@@ -0,0 +1,148 @@
+package sources
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	bible "bibleapp.server/pkg/bible_parser"
+)
+
+/**
+ * Verse is a single verse read from a source, along with the markup that
+ * surrounds it. Offsets into Text are in bytes.
+ */
+type Verse struct {
+	Book    bible.Book
+	Chapter uint
+	Number  uint
+	// The last verse of a bridged verse ("\v 1-2"), otherwise zero.
+	EndNumber uint
+	Text      string
+
+	// Style of the paragraph the verse starts ("p", "q1", "m"), or empty
+	// when the verse continues the previous paragraph.
+	Paragraph string
+	// Headings and titles printed before the verse.
+	Headings []Heading
+	// Footnotes and cross references anchored within the verse.
+	Notes []Note
+	// Character styles applied to parts of the text ("wj", "nd", "add").
+	Spans []Span
+	// Words carrying lexical attributes (Strong's numbers, lemmas).
+	Words []Word
+	// Original-language words behind an interlinear translation, in the
+	// order of the original text.
+	Tokens []Token
+}
+
+// Heading is a section heading or title. Style is the USFM marker ("s1",
+// "ms", "d", "r").
+type Heading struct {
+	Style string
+	Text  string
+}
+
+/**
+ * Note is a footnote (Style "f") or cross reference (Style "x") placed at
+ * Offset in the verse text. References holds the passages a cross reference
+ * points to.
+ */
+type Note struct {
+	Style      string
+	Caller     string
+	Offset     int
+	Text       string
+	References []bible.BibleReference
+}
+
+// Span marks Text[Start:End] with a character style.
+type Span struct {
+	Style string
+	Start int
+	End   int
+}
+
+// Word is Text[Start:End] along with its lexical attributes.
+type Word struct {
+	Start  int
+	End    int
+	Lemma  string
+	Strong string
+	Morph  string
+}
+
+/**
+ * Token is a word of the original-language text behind an interlinear
+ * translation. Text[Start:End] is the part of the translation rendering it;
+ * a word left untranslated has an empty range where it would sit.
+ */
+type Token struct {
+	Language        string // hbo, arc or grc
+	Text            string
+	Transliteration string
+	Lemma           string
+	Strong          string
+	Morph           string
+	// Description of Morph, when the source gives one.
+	MorphLabel string
+	Gloss      string
+	Start      int
+	End        int
+}
+
+/**
+ * Metadata describes the version held by a source, as far as the source
+ * format records it. Anything left empty has to come from the user.
+ */
+type Metadata struct {
+	Abbreviation  string
+	Name          string
+	Language      string
+	Description   string
+	Versification string
+}
+
+/**
+ * SourceReader reads the verses of one version from a file or directory in
+ * some format. Next returns io.EOF once every verse has been read.
+ */
+type SourceReader interface {
+	Metadata() Metadata
+	Next() (Verse, error)
+	Close() error
+}
+
+// Opener opens the source at path for reading.
+type Opener func(path string) (SourceReader, error)
+
+var openers = map[string]Opener{}
+
+// Register makes a source format available to Open. It is meant to be
+// called from the init function of the file implementing the format.
+func Register(format string, open Opener) {
+	format = strings.ToLower(format)
+	if _, exists := openers[format]; exists {
+		panic(fmt.Sprintf("sources: format %q registered twice", format))
+	}
+	openers[format] = open
+}
+
+// Open opens the source at path with the reader registered for format.
+func Open(format string, path string) (SourceReader, error) {
+	open, ok := openers[strings.ToLower(format)]
+	if !ok {
+		return nil, fmt.Errorf("unknown source format %q (known formats: %s)", format, strings.Join(Formats(), ", "))
+	}
+	return open(path)
+}
+
+// Formats returns the names of the registered formats.
+func Formats() []string {
+	var formats []string
+	for format := range openers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
@@ -0,0 +1,500 @@
+package sources
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	bible "bibleapp.server/pkg/bible_parser"
+)
+
+func init() {
+	Register("sword", OpenSword)
+}
+
+// VersificationPath is the versification JSON SWORD modules are read with,
+// relative to the api directory the tools are run from.
+var VersificationPath = "../versification/sword-1.9.0-versification.json"
+
+/**
+ * swordReader reads the Bible text of a CrossWire SWORD module unpacked on
+ * disk, in either the raw (RawText, RawText4) or compressed (zText, zText4)
+ * layout.
+ *
+ * SWORD stores no references with the text. Each testament holds one entry
+ * per position of the module's versification, in order: the module and
+ * testament headings, then for each book its introduction, and for each
+ * chapter its heading (verse 0) followed by its verses. Walking the scheme
+ * from the versification JSON gives the reference of every entry.
+ */
+type swordReader struct {
+	meta       Metadata
+	markup     string
+	latin1     bool
+	testaments [2]*swordTestament
+	entries    []swordEntry
+	parser     *usfmParser
+}
+
+// swordEntry is the position of a chapter heading (Verse 0) or verse
+// within a testament.
+type swordEntry struct {
+	book      bible.Book
+	chapter   uint
+	verse     uint
+	testament int
+	index     int
+}
+
+/**
+ * OpenSword opens a SWORD module. path is the module's .conf file, a SWORD
+ * directory holding a single module (with mods.d/ and modules/), or the
+ * module's data directory itself, which is then read as a KJV versified
+ * OSIS module in UTF-8.
+ */
+func OpenSword(path string) (SourceReader, error) {
+	confPath, err := swordConfPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	name, conf := "", map[string]string{}
+	dataPath := path
+	if confPath != "" {
+		name, conf, err = readSwordConf(confPath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", confPath, err)
+		}
+		// DataPath is relative to the directory holding mods.d
+		root := filepath.Dir(filepath.Dir(confPath))
+		dataPath = filepath.Join(root, conf["DataPath"])
+	}
+	if conf["CipherKey"] != "" {
+		return nil, fmt.Errorf("module %s is encrypted", name)
+	}
+
+	scheme := conf["Versification"]
+	if scheme == "" {
+		scheme = "KJV"
+	}
+	schemes, err := bible.LoadVersifications(VersificationPath)
+	if err != nil {
+		return nil, err
+	}
+	v11n, ok := schemes[scheme]
+	if !ok {
+		return nil, fmt.Errorf("unknown versification %q (known schemes: %s)", scheme, strings.Join(bible.SchemeNames(schemes), ", "))
+	}
+
+	r := &swordReader{
+		meta: Metadata{
+			Abbreviation:  name,
+			Name:          conf["Description"],
+			Language:      conf["Lang"],
+			Description:   swordAbout(conf["About"]),
+			Versification: scheme,
+		},
+		markup: strings.ToUpper(conf["SourceType"]),
+		// Modules are Latin-1 unless their .conf says Encoding=UTF-8
+		latin1: !strings.EqualFold(conf["Encoding"], "UTF-8"),
+		parser: &usfmParser{},
+	}
+	if confPath == "" {
+		r.markup = "OSIS"
+		r.latin1 = false
+	}
+
+	driver := strings.ToLower(conf["ModDrv"])
+	for t, prefix := range []string{"ot", "nt"} {
+		testament, err := openSwordTestament(dataPath, prefix, driver, conf["CompressType"])
+		if err != nil {
+			return nil, err
+		}
+		r.testaments[t] = testament
+	}
+	if r.testaments[0] == nil && r.testaments[1] == nil {
+		return nil, fmt.Errorf("no ot or nt text in %s", dataPath)
+	}
+
+	r.entries = swordEntries(v11n)
+	return r, nil
+}
+
+// swordConfPath finds the .conf describing the module at path, or returns ""
+// for a bare data directory.
+func swordConfPath(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return path, nil
+	}
+
+	confs, err := filepath.Glob(filepath.Join(path, "mods.d", "*.conf"))
+	if err != nil {
+		return "", err
+	}
+	switch len(confs) {
+	case 0:
+		return "", nil
+	case 1:
+		return confs[0], nil
+	}
+	return "", fmt.Errorf("%s holds %d modules, give the .conf of the one to read", path, len(confs))
+}
+
+/**
+ * readSwordConf reads a module's .conf: the module name in brackets followed
+ * by Key=Value lines. Values ending in a backslash continue on the next
+ * line; repeated keys keep their first value.
+ */
+func readSwordConf(path string) (string, map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer file.Close()
+
+	name := ""
+	conf := map[string]string{}
+	key, value := "", ""
+	store := func() {
+		if _, exists := conf[key]; key != "" && !exists {
+			conf[key] = strings.TrimSpace(value)
+		}
+		key, value = "", ""
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if key != "" {
+			// Continuation of the previous value
+			value += strings.TrimSuffix(line, "\\")
+			if !strings.HasSuffix(line, "\\") {
+				store()
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if name == "" && strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			name = trimmed[1 : len(trimmed)-1]
+			continue
+		}
+		k, v, found := strings.Cut(line, "=")
+		if !found || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		key, value = strings.TrimSpace(k), strings.TrimSuffix(v, "\\")
+		if !strings.HasSuffix(v, "\\") {
+			store()
+		}
+	}
+	store()
+	if err := scanner.Err(); err != nil {
+		return "", nil, err
+	}
+	if name == "" {
+		return "", nil, errors.New("no module name")
+	}
+	return name, conf, nil
+}
+
+var rtfControlRe = regexp.MustCompile(`\\[a-z]+\s?`)
+
+// swordAbout turns the RTF-ish About of a .conf into plain text.
+func swordAbout(about string) string {
+	about = strings.ReplaceAll(about, `\par`, "\n")
+	about = rtfControlRe.ReplaceAllString(about, "")
+	return strings.TrimSpace(about)
+}
+
+// swordEntries lists the position of every chapter heading and verse of a
+// scheme, in the order the reader walks them.
+func swordEntries(v11n *bible.Versification) []swordEntry {
+	var entries []swordEntry
+	// Entries 0 and 1 are the module and testament headings
+	next := [2]int{2, 2}
+	for _, book := range v11n.Books() {
+		t := 0
+		if (book >= bible.Matthew && book <= bible.Revelation) || book == bible.Laodiceans {
+			t = 1
+		}
+		// Book introduction
+		next[t]++
+
+		for chapter, count := range v11n.VerseCounts(book) {
+			for verse := uint(0); verse <= count; verse++ {
+				entries = append(entries, swordEntry{
+					book:      book,
+					chapter:   uint(chapter + 1),
+					verse:     verse,
+					testament: t,
+					index:     next[t],
+				})
+				next[t]++
+			}
+		}
+	}
+	return entries
+}
+
+func (r *swordReader) Metadata() Metadata {
+	return r.meta
+}
+
+func (r *swordReader) Next() (Verse, error) {
+	p := r.parser
+	for len(p.verses) == 0 {
+		if len(r.entries) == 0 {
+			return Verse{}, io.EOF
+		}
+		entry := r.entries[0]
+		r.entries = r.entries[1:]
+
+		testament := r.testaments[entry.testament]
+		if testament == nil {
+			continue
+		}
+		text, err := testament.entry(entry.index)
+		if err != nil {
+			return Verse{}, fmt.Errorf("%s %d:%d: %w", entry.book, entry.chapter, entry.verse, err)
+		}
+		if len(bytes.TrimSpace(text)) == 0 {
+			continue
+		}
+		if err := r.parse(entry, text); err != nil {
+			return Verse{}, fmt.Errorf("%s %d:%d: %w", entry.book, entry.chapter, entry.verse, err)
+		}
+	}
+
+	v := p.verses[0]
+	p.verses = p.verses[1:]
+	return v, nil
+}
+
+// parse feeds the text of one entry to the parser. Chapter headings only
+// contribute headings to the verse following them.
+func (r *swordReader) parse(entry swordEntry, text []byte) error {
+	p := r.parser
+	if r.latin1 {
+		text = latin1ToUTF8(text)
+	}
+
+	if !p.hasBook || p.book != entry.book {
+		if err := p.token(usfmToken{marker: "id"}); err != nil {
+			return err
+		}
+		if err := p.token(usfmToken{text: entry.book.Code() + " "}); err != nil {
+			return err
+		}
+	}
+	if p.intro || p.chapter != entry.chapter {
+		if err := p.token(usfmToken{marker: "c"}); err != nil {
+			return err
+		}
+		if err := p.token(usfmToken{text: fmt.Sprintf("%d ", entry.chapter)}); err != nil {
+			return err
+		}
+	}
+	if entry.verse > 0 {
+		if err := p.token(usfmToken{marker: "v"}); err != nil {
+			return err
+		}
+		if err := p.token(usfmToken{text: fmt.Sprintf("%d ", entry.verse)}); err != nil {
+			return err
+		}
+	}
+
+	if r.markup != "OSIS" {
+		// ThML, GBF and plain text modules: keep the text only
+		err := p.token(usfmToken{text: swordTagRe.ReplaceAllString(string(text), "")})
+		p.endVerse()
+		return err
+	}
+
+	// Entries are fragments, possibly using HTML entities
+	decoder := xml.NewDecoder(bytes.NewReader(text))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	_, err := p.parseXML(decoder, func(e xml.StartElement) (func() error, bool, error) {
+		if e.Name.Local == "verse" {
+			// The entry is the verse
+			return nil, false, nil
+		}
+		return p.osisElement(e)
+	})
+	return err
+}
+
+var swordTagRe = regexp.MustCompile(`<[^>]*>`)
+
+func latin1ToUTF8(text []byte) []byte {
+	runes := make([]rune, len(text))
+	for i, b := range text {
+		runes[i] = rune(b)
+	}
+	return []byte(string(runes))
+}
+
+func (r *swordReader) Close() error {
+	return nil
+}
+
+/**
+ * swordTestament is the text of one testament. Raw modules index the text
+ * directly from .vss: a 4 byte offset and a 2 byte size (4 bytes for
+ * RawText4) per entry. zText modules index blocks of compressed verses:
+ * .bzv holds a 4 byte block number, 4 byte offset and 2 byte size (4 for
+ * zText4) per entry, and .bzs a 4 byte offset, compressed size and
+ * uncompressed size per block of .bzz. All numbers are little endian.
+ */
+type swordTestament struct {
+	index      []byte
+	entrySize  int
+	sizeBytes  int
+	data       []byte
+	blocks     []byte
+	decompress func([]byte) ([]byte, error)
+
+	// The last block decompressed
+	block     int
+	blockText []byte
+}
+
+// openSwordTestament opens the "ot" or "nt" files of a module, returning
+// nil if the module does not have that testament.
+func openSwordTestament(dir string, prefix string, driver string, compression string) (*swordTestament, error) {
+	base := filepath.Join(dir, prefix)
+	t := &swordTestament{block: -1, sizeBytes: 2}
+	if strings.HasSuffix(driver, "4") {
+		t.sizeBytes = 4
+	}
+
+	compressed := strings.HasPrefix(driver, "z")
+	if driver == "" {
+		// No .conf: go by the files present
+		_, err := os.Stat(base + ".bzv")
+		compressed = err == nil
+	}
+
+	var err error
+	if !compressed {
+		t.entrySize = 4 + t.sizeBytes
+		if t.index, err = os.ReadFile(base + ".vss"); errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if t.data, err = os.ReadFile(base); err != nil {
+			return nil, err
+		}
+		return t, nil
+	}
+
+	t.entrySize = 8 + t.sizeBytes
+	if t.index, err = os.ReadFile(base + ".bzv"); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if t.blocks, err = os.ReadFile(base + ".bzs"); err != nil {
+		return nil, err
+	}
+	if t.data, err = os.ReadFile(base + ".bzz"); err != nil {
+		return nil, err
+	}
+
+	switch strings.ToUpper(compression) {
+	case "", "ZIP":
+		t.decompress = func(b []byte) ([]byte, error) {
+			z, err := zlib.NewReader(bytes.NewReader(b))
+			if err != nil {
+				return nil, err
+			}
+			defer z.Close()
+			return io.ReadAll(z)
+		}
+	case "BZIP2":
+		t.decompress = func(b []byte) ([]byte, error) {
+			return io.ReadAll(bzip2.NewReader(bytes.NewReader(b)))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported compression %s", compression)
+	}
+	return t, nil
+}
+
+// entry returns the text of entry i, or nothing if the index ends before
+// it.
+func (t *swordTestament) entry(i int) ([]byte, error) {
+	offset := i * t.entrySize
+	if offset+t.entrySize > len(t.index) {
+		return nil, nil
+	}
+	record := t.index[offset : offset+t.entrySize]
+	size := t.size(record[t.entrySize-t.sizeBytes:])
+
+	if t.decompress == nil {
+		start := int(binary.LittleEndian.Uint32(record))
+		return t.slice(t.data, start, size)
+	}
+
+	if size == 0 {
+		return nil, nil
+	}
+	block := int(binary.LittleEndian.Uint32(record))
+	if block != t.block {
+		if err := t.readBlock(block); err != nil {
+			return nil, err
+		}
+	}
+	start := int(binary.LittleEndian.Uint32(record[4:]))
+	return t.slice(t.blockText, start, size)
+}
+
+func (t *swordTestament) readBlock(block int) error {
+	offset := block * 12
+	if offset+12 > len(t.blocks) {
+		return fmt.Errorf("block %d out of range", block)
+	}
+	record := t.blocks[offset : offset+12]
+	start := int(binary.LittleEndian.Uint32(record))
+	compressed, err := t.slice(t.data, start, int(binary.LittleEndian.Uint32(record[4:])))
+	if err != nil {
+		return err
+	}
+	text, err := t.decompress(compressed)
+	if err != nil {
+		return fmt.Errorf("block %d: %w", block, err)
+	}
+	t.block, t.blockText = block, text
+	return nil
+}
+
+func (t *swordTestament) size(b []byte) int {
+	if t.sizeBytes == 4 {
+		return int(binary.LittleEndian.Uint32(b))
+	}
+	return int(binary.LittleEndian.Uint16(b))
+}
+
+func (t *swordTestament) slice(data []byte, start int, size int) ([]byte, error) {
+	if start < 0 || start+size > len(data) {
+		return nil, fmt.Errorf("entry at %d+%d outside of the text", start, size)
+	}
+	return data[start : start+size], nil
+}
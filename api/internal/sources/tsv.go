@@ -0,0 +1,76 @@
+package sources
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	bible "bibleapp.server/pkg/bible_parser"
+)
+
+func init() {
+	Register("tsv", OpenTSV)
+}
+
+/**
+ * tsvReader reads plain text sources with one verse per line as
+ * "Book C:V<TAB>text", e.g. "1 John 2:1	My little children...". Lines
+ * without a tab are skipped, which covers titles and blank lines.
+ */
+type tsvReader struct {
+	file    *os.File
+	scanner *bufio.Scanner
+	line    int
+}
+
+// OpenTSV opens a "Book C:V<TAB>text" file.
+func OpenTSV(path string) (SourceReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return &tsvReader{file: file, scanner: scanner}, nil
+}
+
+func (r *tsvReader) Metadata() Metadata {
+	return Metadata{}
+}
+
+func (r *tsvReader) Next() (Verse, error) {
+	for r.scanner.Scan() {
+		r.line++
+		location, text, found := strings.Cut(r.scanner.Text(), "\t")
+		if !found {
+			continue
+		}
+
+		ref, err := bible.NormalizeBibleReference(location)
+		if err != nil {
+			return Verse{}, fmt.Errorf("line %d: %w", r.line, err)
+		}
+		if ref.Granularity != bible.GRANULARITY_VERSE || ref.StartChapter != ref.EndChapter || ref.StartVerse != ref.EndVerse {
+			return Verse{}, fmt.Errorf("line %d: %q is not a single verse", r.line, location)
+		}
+
+		return Verse{
+			Book:    ref.Book,
+			Chapter: ref.StartChapter,
+			Number:  ref.StartVerse,
+			Text:    strings.TrimSpace(text),
+		}, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return Verse{}, err
+	}
+	return Verse{}, io.EOF
+}
+
+func (r *tsvReader) Close() error {
+	return r.file.Close()
+}
@@ -0,0 +1,498 @@
+package sources
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	bible "bibleapp.server/pkg/bible_parser"
+)
+
+func init() {
+	Register("usfm", OpenUSFM)
+}
+
+// OpenUSFM opens a USFM file or a directory of them, one book per file.
+func OpenUSFM(path string) (SourceReader, error) {
+	files, err := sourceFiles(path, ".usfm", ".sfm")
+	if err != nil {
+		return nil, err
+	}
+	return &bookReader{files: files, parse: func(content []byte) ([]Verse, error) {
+		return ParseUSFM(string(content))
+	}}, nil
+}
+
+// usfmToken is a marker ("\v", "\wj*") or the text between markers.
+type usfmToken struct {
+	marker  string
+	closing bool
+	text    string
+}
+
+// tokenizeUSFM splits USFM into markers and text. The space ending an
+// opening marker is dropped; "\+wj" nested markers lose their "+".
+func tokenizeUSFM(usfm string) []usfmToken {
+	var tokens []usfmToken
+	for i := 0; i < len(usfm); {
+		if usfm[i] != '\\' {
+			end := strings.IndexByte(usfm[i:], '\\')
+			if end < 0 {
+				end = len(usfm) - i
+			}
+			tokens = append(tokens, usfmToken{text: usfm[i : i+end]})
+			i += end
+			continue
+		}
+
+		j := i + 1
+		for j < len(usfm) && (isMarkerByte(usfm[j])) {
+			j++
+		}
+		token := usfmToken{marker: strings.TrimPrefix(usfm[i+1:j], "+")}
+		if j < len(usfm) && usfm[j] == '*' {
+			token.closing = true
+			j++
+		} else if j < len(usfm) && (usfm[j] == ' ' || usfm[j] == '\n' || usfm[j] == '\r' || usfm[j] == '\t') {
+			j++
+		}
+		tokens = append(tokens, token)
+		i = j
+	}
+	return tokens
+}
+
+func isMarkerByte(c byte) bool {
+	return c == '+' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+var (
+	usfmLevelRe = regexp.MustCompile(`^([a-z]+?)[0-9]?$`)
+	usfmAttrRe  = regexp.MustCompile(`([A-Za-z][A-Za-z0-9-]*)="([^"]*)"`)
+	spaceRe     = regexp.MustCompile(`\s+`)
+)
+
+// Marker classes, keyed by the marker without its level number.
+var (
+	usfmHeadings = map[string]bool{
+		"s": true, "ms": true, "mr": true, "r": true, "sr": true, "d": true,
+		"sp": true, "qa": true, "sd": true,
+	}
+	usfmParagraphs = map[string]bool{
+		"p": true, "m": true, "po": true, "pr": true, "cls": true, "pmo": true,
+		"pm": true, "pmc": true, "pmr": true, "pi": true, "mi": true, "nb": true,
+		"pc": true, "ph": true, "b": true, "q": true, "qr": true, "qc": true,
+		"qm": true, "qd": true, "lh": true, "li": true, "lf": true, "lim": true,
+		"tr": true, "th": true, "thr": true, "tc": true, "tcr": true, "pb": true,
+	}
+	usfmNotes = map[string]bool{
+		"f": true, "fe": true, "ef": true, "x": true, "ex": true,
+	}
+	// Markers whose content, up to their closing marker, is not verse text.
+	usfmSkipped = map[string]bool{
+		"fig": true, "va": true, "vp": true, "ca": true, "rq": true, "cat": true,
+		"esb": true,
+	}
+	// Note content that is not part of the note text: the origin
+	// reference ("1.2: ") and the verse a note is about.
+	usfmNoteOrigin = map[string]bool{
+		"fr": true, "xo": true, "fv": true,
+	}
+	usfmSkippedClose = map[string]string{"esb": "esbe"}
+	// Line markers whose text is skipped up to the next marker.
+	usfmSkippedLine = map[string]bool{
+		"cl": true, "cp": true, "cd": true,
+	}
+)
+
+// usfmParser builds verses from a token stream.
+type usfmParser struct {
+	book    bible.Book
+	hasBook bool
+	chapter uint
+
+	verses  []Verse
+	current *Verse
+	text    strings.Builder
+
+	paragraph string
+	headings  []Heading
+	heading   *Heading
+
+	// Content of a marker being skipped, see usfmSkipped. intro skips
+	// everything up to the next chapter.
+	skip  string
+	intro bool
+	// Inside a milestone, up to its "\*": its text is attributes
+	milestone bool
+
+	// The marker whose argument ("\c 1", "\v 2-3", note caller) comes next
+	pendingArgument string
+	err             error
+
+	note    *Note
+	noteTo  strings.Builder
+	inXT    bool
+	inFR    bool
+	targets strings.Builder
+
+	spans []Span
+}
+
+/**
+ * ParseUSFM reads the verses of one USFM book. The \id marker must come
+ * first and name a book we know.
+ */
+func ParseUSFM(usfm string) ([]Verse, error) {
+	p := &usfmParser{}
+	for _, token := range tokenizeUSFM(usfm) {
+		if err := p.token(token); err != nil {
+			return nil, err
+		}
+	}
+	p.endVerse()
+	return p.verses, nil
+}
+
+func (p *usfmParser) token(t usfmToken) error {
+	if t.marker == "" && !t.closing {
+		if p.milestone {
+			return nil
+		}
+		if p.note != nil {
+			p.noteText(t.text)
+		} else {
+			p.addText(t.text)
+		}
+		return p.err
+	}
+
+	// Milestones ("\qt-s |sid="q1"\*") carry no text, only attributes
+	p.milestone = strings.HasSuffix(t.marker, "-s") || strings.HasSuffix(t.marker, "-e")
+	if p.milestone || t.marker == "" {
+		return nil
+	}
+
+	if usfmSkippedLine[p.skip] {
+		p.skip = ""
+	}
+	if p.skip != "" {
+		if (t.closing && t.marker == p.skip) || t.marker == usfmSkippedClose[p.skip] {
+			p.skip = ""
+		}
+		return nil
+	}
+
+	if p.note != nil {
+		return p.noteToken(t)
+	}
+
+	if t.closing {
+		p.closeSpan(t.marker)
+		return nil
+	}
+
+	class := t.marker
+	if m := usfmLevelRe.FindStringSubmatch(t.marker); m != nil {
+		class = m[1]
+	}
+
+	switch {
+	case t.marker == "id":
+		p.endVerse()
+		p.hasBook = false
+		p.intro = true
+		p.paragraph = ""
+		p.headings = nil
+		p.pendingArgument = "id"
+	case t.marker == "c":
+		p.endVerse()
+		p.intro = false
+		p.heading = nil
+		p.pendingArgument = "c"
+	case t.marker == "v":
+		p.endVerse()
+		p.heading = nil
+		p.pendingArgument = "v"
+	case p.intro:
+		// Book headers and introductions
+	case usfmSkipped[t.marker] || usfmSkippedLine[t.marker]:
+		p.skip = t.marker
+	case usfmNotes[t.marker]:
+		p.note = &Note{Style: t.marker[:1], Offset: p.text.Len()}
+		if t.marker == "ef" || t.marker == "ex" {
+			p.note.Style = t.marker[1:]
+		}
+		p.noteTo.Reset()
+		p.targets.Reset()
+		p.pendingArgument = "caller"
+	case usfmHeadings[class] && p.current != nil && p.text.Len() == 0:
+		// A title at the start of a verse, as in the Psalms of SWORD modules
+		p.current.Headings = append(p.current.Headings, Heading{Style: t.marker})
+		p.heading = &p.current.Headings[len(p.current.Headings)-1]
+	case usfmHeadings[class]:
+		p.headings = append(p.headings, Heading{Style: t.marker})
+		p.heading = &p.headings[len(p.headings)-1]
+	case usfmParagraphs[class]:
+		p.heading = nil
+		if t.marker == "nb" || t.marker == "pb" {
+			break
+		}
+		// Whether the paragraph starts the next verse or continues this
+		// one depends on what follows, see addText.
+		p.paragraph = t.marker
+	default:
+		// Character style, closed by the matching "\marker*"
+		if p.heading == nil && p.current != nil {
+			p.spans = append(p.spans, Span{Style: t.marker, Start: p.text.Len(), End: -1})
+		}
+	}
+	return nil
+}
+
+func (p *usfmParser) noteToken(t usfmToken) error {
+	if t.marker == "" {
+		p.noteText(t.text)
+		return nil
+	}
+	if t.closing {
+		if usfmNotes[t.marker] {
+			p.endNote()
+		}
+		if t.marker == "xt" {
+			p.inXT = false
+		}
+		if usfmNoteOrigin[t.marker] {
+			p.inFR = false
+		}
+		return nil
+	}
+
+	p.inFR = usfmNoteOrigin[t.marker]
+	p.inXT = t.marker == "xt"
+	if t.marker == "xt" && p.targets.Len() > 0 {
+		p.targets.WriteString("; ")
+	}
+	return nil
+}
+
+func (p *usfmParser) noteText(text string) {
+	if p.pendingArgument == "caller" {
+		p.pendingArgument = ""
+		fields := strings.Fields(text)
+		if len(fields) > 0 {
+			p.note.Caller = fields[0]
+			text = strings.TrimLeftFunc(text, unicode.IsSpace)[len(fields[0]):]
+		}
+	}
+	if p.inFR {
+		return
+	}
+	if p.inXT {
+		p.targets.WriteString(stripAttributes(text))
+	}
+	p.noteTo.WriteString(stripAttributes(text))
+}
+
+func (p *usfmParser) endNote() {
+	note := p.note
+	p.note = nil
+	p.inFR, p.inXT = false, false
+
+	note.Text = strings.TrimSpace(spaceRe.ReplaceAllString(p.noteTo.String(), " "))
+	for _, match := range bible.FindReferences(p.targets.String()) {
+		note.References = append(note.References, match.References...)
+	}
+	if p.current != nil {
+		p.current.Notes = append(p.current.Notes, *note)
+	}
+}
+
+func (p *usfmParser) addText(text string) {
+	switch p.pendingArgument {
+	case "id", "c", "v":
+		argument := p.pendingArgument
+		p.pendingArgument = ""
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			return
+		}
+		text = strings.TrimLeftFunc(text, unicode.IsSpace)[len(fields[0]):]
+		if err := p.argument(argument, fields[0]); err != nil {
+			p.err = err
+			return
+		}
+		if argument != "v" {
+			return
+		}
+	}
+
+	if p.intro || p.skip != "" {
+		return
+	}
+	if p.heading != nil {
+		p.heading.Text = strings.TrimSpace(p.heading.Text + " " + strings.TrimSpace(stripAttributes(text)))
+		return
+	}
+	if p.current == nil {
+		return
+	}
+
+	// \w gracious|strong="H2603"\w*
+	if len(p.spans) > 0 {
+		if word, attrs, found := strings.Cut(text, "|"); found {
+			p.addWord(word, attrs)
+			return
+		}
+	}
+
+	text = spaceRe.ReplaceAllString(text, " ")
+	if p.paragraph != "" && strings.TrimSpace(text) != "" {
+		if p.text.Len() == 0 {
+			p.current.Paragraph = p.paragraph
+		} else {
+			// A new line within the verse
+			text = " " + text
+		}
+		p.paragraph = ""
+	}
+	if p.text.Len() == 0 || strings.HasSuffix(p.text.String(), " ") {
+		text = strings.TrimLeft(text, " ")
+	}
+	p.text.WriteString(text)
+}
+
+// addWord adds the text of a "\w" word and records its attributes.
+func (p *usfmParser) addWord(word string, attrs string) {
+	var w Word
+	matches := usfmAttrRe.FindAllStringSubmatch(attrs, -1)
+	if len(matches) == 0 {
+		// The default attribute of \w is the lemma
+		w.Lemma = strings.TrimSpace(attrs)
+	}
+	for _, m := range matches {
+		switch m[1] {
+		case "lemma":
+			w.Lemma = m[2]
+		case "strong":
+			w.Strong = m[2]
+		case "x-morph", "morph":
+			w.Morph = m[2]
+		}
+	}
+	p.word(word, w)
+}
+
+// word adds text to the verse, recording w for it if it has attributes.
+func (p *usfmParser) word(text string, w Word) {
+	p.addText(text)
+	if p.current == nil || p.heading != nil || (w.Lemma == "" && w.Strong == "" && w.Morph == "") {
+		return
+	}
+	w.End = len(strings.TrimRight(p.text.String(), " "))
+	w.Start = w.End - len(strings.TrimSpace(spaceRe.ReplaceAllString(text, " ")))
+	p.current.Words = append(p.current.Words, w)
+}
+
+// stripAttributes drops the "|attributes" of character markup in notes and
+// headings.
+func stripAttributes(text string) string {
+	if before, _, found := strings.Cut(text, "|"); found {
+		return before
+	}
+	return text
+}
+
+func (p *usfmParser) argument(marker string, value string) error {
+	switch marker {
+	case "id":
+		book, ok := bible.BookFromCode(value)
+		if !ok {
+			return fmt.Errorf("unknown book code %q", value)
+		}
+		p.book, p.hasBook = book, true
+		p.chapter = 0
+	case "c":
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid chapter %q", value)
+		}
+		p.chapter = uint(n)
+	case "v":
+		if !p.hasBook || p.chapter == 0 {
+			return fmt.Errorf("verse %s outside of a chapter", value)
+		}
+		first, last, _ := strings.Cut(value, "-")
+		number, err := parseVerseNumber(first)
+		if err != nil {
+			return err
+		}
+		p.current = &Verse{
+			Book:      p.book,
+			Chapter:   p.chapter,
+			Number:    number,
+			Paragraph: p.paragraph,
+			Headings:  p.headings,
+		}
+		if last != "" {
+			end, err := parseVerseNumber(last)
+			if err != nil {
+				return err
+			}
+			p.current.EndNumber = end
+		}
+		p.paragraph = ""
+		p.headings = nil
+		p.text.Reset()
+		p.spans = nil
+	}
+	return nil
+}
+
+// parseVerseNumber reads a verse number, ignoring segment letters ("4a").
+func parseVerseNumber(value string) (uint, error) {
+	digits := strings.TrimRightFunc(value, unicode.IsLetter)
+	n, err := strconv.ParseUint(digits, 10, 32)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("invalid verse %q", value)
+	}
+	return uint(n), nil
+}
+
+func (p *usfmParser) closeSpan(marker string) {
+	for i := len(p.spans) - 1; i >= 0; i-- {
+		if p.spans[i].Style == marker && p.spans[i].End < 0 {
+			p.spans[i].End = p.text.Len()
+			return
+		}
+	}
+}
+
+func (p *usfmParser) endVerse() {
+	if p.current == nil {
+		return
+	}
+
+	text := strings.TrimRight(p.text.String(), " ")
+	p.current.Text = text
+	for _, span := range p.spans {
+		if span.End < 0 || span.End > len(text) {
+			span.End = len(text)
+		}
+		if span.Start < span.End {
+			p.current.Spans = append(p.current.Spans, span)
+		}
+	}
+	for i := range p.current.Notes {
+		if p.current.Notes[i].Offset > len(text) {
+			p.current.Notes[i].Offset = len(text)
+		}
+	}
+
+	p.verses = append(p.verses, *p.current)
+	p.current = nil
+	p.spans = nil
+	p.text.Reset()
+}
@@ -0,0 +1,40 @@
+package sources
+
+import (
+	"testing"
+
+	bible "bibleapp.server/pkg/bible_parser"
+)
+
+func TestParseUSFMText(t *testing.T) {
+	tests := []struct {
+		name string
+		usfm string
+		want string
+	}{
+		{"plain", `\v 1 In the beginning`, "In the beginning"},
+		{"milestone with attributes", `\v 3 \qt-s |who="Pilate"\*What is truth?\qt-e\*`, "What is truth?"},
+		{"milestone with default attribute", `\v 3 \qt-s |sid="q1"\*What is truth?\qt-e |eid="q1"\*`, "What is truth?"},
+		{"milestone without attributes", `\v 3 Jesus said, \qt-s\*What is truth?\qt-e\* and went out`, "Jesus said, What is truth? and went out"},
+		{"standalone milestone", `\v 3 What \ts\*is truth?`, "What is truth?"},
+		{"word attributes", `\v 3 \w truth|strong="G225"\w*?`, "truth?"},
+	}
+	for _, tt := range tests {
+		verses, err := ParseUSFM(`\id JHN` + "\n" + `\c 18` + "\n" + tt.usfm)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if len(verses) != 1 {
+			t.Errorf("%s: %d verses, want 1", tt.name, len(verses))
+			continue
+		}
+		v := verses[0]
+		if v.Book != bible.John || v.Chapter != 18 {
+			t.Errorf("%s: verse in %v %d, want John 18", tt.name, v.Book, v.Chapter)
+		}
+		if v.Text != tt.want {
+			t.Errorf("%s: text %q, want %q", tt.name, v.Text, tt.want)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package sources
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+func init() {
+	Register("usx", OpenUSX)
+}
+
+// OpenUSX opens a USX file or a directory of them, one book per file.
+func OpenUSX(path string) (SourceReader, error) {
+	files, err := sourceFiles(path, ".usx", ".xml")
+	if err != nil {
+		return nil, err
+	}
+	return &bookReader{files: files, parse: func(content []byte) ([]Verse, error) {
+		return ParseUSX(bytes.NewReader(content))
+	}}, nil
+}
+
+/**
+ * ParseUSX reads the verses of one USX 3.x book. USX is USFM written as XML,
+ * with the marker of each element in its style attribute, so the elements
+ * are fed to the USFM parser as the markers they stand for:
+ *
+ *   <book code="GEN">             \id GEN
+ *   <chapter number="1" sid=..>   \c 1
+ *   <verse number="1" sid=..>     \v 1
+ *   <para style="s1">             \s1
+ *   <note style="f" caller="+">   \f + ... \f*
+ *   <char style="wj">             \wj ... \wj*
+ *
+ * Chapter and verse end milestones (eid) are ignored: a verse runs until the
+ * next one starts. Peripheral books (<periph>) are skipped.
+ */
+func ParseUSX(r io.Reader) ([]Verse, error) {
+	p := &usfmParser{}
+	decoder := xml.NewDecoder(r)
+
+	// The USFM markers closing each open element, "" for those without one.
+	var closing []string
+	// Depth of a skipped element, or 0.
+	skipDepth := 0
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if skipDepth > 0 {
+				skipDepth++
+				continue
+			}
+			tokens, skip := usxTokens(t)
+			if skip {
+				skipDepth = 1
+				continue
+			}
+			for _, tok := range tokens {
+				if err := p.token(tok); err != nil {
+					return nil, err
+				}
+			}
+			marker := ""
+			switch t.Name.Local {
+			case "note", "char", "figure":
+				marker = attr(t, "style")
+			case "sidebar":
+				marker = "esbe"
+			}
+			if t.Name.Local == "char" && marker == "w" {
+				word, err := readUSXWord(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+				if p.note != nil {
+					p.noteText(word.text)
+				} else {
+					p.word(word.text, word.Word)
+				}
+				if err := p.token(usfmToken{marker: marker, closing: true}); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			closing = append(closing, marker)
+
+		case xml.EndElement:
+			if skipDepth > 0 {
+				skipDepth--
+				continue
+			}
+			if len(closing) == 0 {
+				continue
+			}
+			marker := closing[len(closing)-1]
+			closing = closing[:len(closing)-1]
+			if marker == "" {
+				continue
+			}
+			if err := p.token(usfmToken{marker: marker, closing: marker != "esbe"}); err != nil {
+				return nil, err
+			}
+
+		case xml.CharData:
+			if skipDepth > 0 {
+				continue
+			}
+			if err := p.token(usfmToken{text: string(t)}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	p.endVerse()
+	return p.verses, nil
+}
+
+// usxTokens returns the USFM tokens an opening element stands for, or
+// whether the element is to be skipped entirely.
+func usxTokens(e xml.StartElement) ([]usfmToken, bool) {
+	style := attr(e, "style")
+	switch e.Name.Local {
+	case "book":
+		return []usfmToken{{marker: "id"}, {text: attr(e, "code") + " "}}, false
+	case "chapter":
+		if attr(e, "eid") != "" {
+			return nil, false
+		}
+		return []usfmToken{{marker: "c"}, {text: attr(e, "number") + " "}}, false
+	case "verse":
+		if attr(e, "eid") != "" {
+			return nil, false
+		}
+		return []usfmToken{{marker: "v"}, {text: attr(e, "number") + " "}}, false
+	case "para", "row", "cell", "figure":
+		return []usfmToken{{marker: style}}, false
+	case "note":
+		return []usfmToken{{marker: style}, {text: attr(e, "caller") + " "}}, false
+	case "char":
+		return []usfmToken{{marker: style}}, false
+	case "sidebar":
+		return []usfmToken{{marker: "esb"}}, false
+	case "periph":
+		return nil, true
+	}
+	// usx, table, ref, ms, optbreak, link: no marker of their own
+	return nil, false
+}
+
+// usxWord is the text of a <char style="w"> element with its attributes.
+type usxWord struct {
+	Word
+	text string
+}
+
+// readUSXWord reads a <char style="w"> element up to its end.
+func readUSXWord(decoder *xml.Decoder, e xml.StartElement) (usxWord, error) {
+	w := usxWord{Word: Word{
+		Lemma:  attr(e, "lemma"),
+		Strong: attr(e, "strong"),
+		Morph:  attr(e, "x-morph"),
+	}}
+	if w.Morph == "" {
+		w.Morph = attr(e, "morph")
+	}
+
+	var text bytes.Buffer
+	for depth := 1; depth > 0; {
+		token, err := decoder.Token()
+		if err != nil {
+			return w, fmt.Errorf("reading word: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			text.Write(t)
+		}
+	}
+	w.text = text.String()
+	return w, nil
+}
+
+// attr returns the value of the named attribute of e, or "".
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
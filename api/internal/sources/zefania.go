@@ -0,0 +1,292 @@
+package sources
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	bible "bibleapp.server/pkg/bible_parser"
+)
+
+func init() {
+	Register("zefania", OpenZefania)
+}
+
+// OpenZefania opens a Zefania XML bible or a directory of them. The version
+// is described by the <INFORMATION> of the first file.
+func OpenZefania(path string) (SourceReader, error) {
+	files, err := sourceFiles(path, ".xml")
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(files[0])
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	meta, err := zefaniaMetadata(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", files[0], err)
+	}
+
+	return &bookReader{files: files, meta: meta, parse: func(content []byte) ([]Verse, error) {
+		return ParseZefania(bytes.NewReader(content), meta.Language)
+	}}, nil
+}
+
+// zefaniaMetadata reads the version description from <INFORMATION>.
+func zefaniaMetadata(r io.Reader) (Metadata, error) {
+	var meta Metadata
+	decoder := xml.NewDecoder(r)
+	field := ""
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return meta, nil
+		}
+		if err != nil {
+			return meta, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			field = strings.ToLower(t.Name.Local)
+			switch field {
+			case "xmlbible":
+				meta.Name = attr(t, "biblename")
+			case "biblebook":
+				// The information is over
+				return meta, nil
+			}
+		case xml.EndElement:
+			field = ""
+		case xml.CharData:
+			text := strings.TrimSpace(string(t))
+			if text == "" {
+				continue
+			}
+			switch field {
+			case "title":
+				meta.Name = text
+			case "identifier":
+				meta.Abbreviation = text
+			case "language":
+				meta.Language = strings.ToLower(text)
+			case "description":
+				meta.Description = text
+			}
+		}
+	}
+}
+
+/*
+ * zefaniaBooks maps the Zefania book numbers beyond the 66 Protestant books
+ * (which are numbered 1 to 66 in order) onto books. The additions to Daniel
+ * and Esther are stored as the Greek books, which contain them.
+ */
+var zefaniaBooks = map[int]bible.Book{
+	67: bible.Judith,
+	68: bible.Wisdom,
+	69: bible.Tobit,
+	70: bible.Sirach,
+	71: bible.Baruch,
+	72: bible.Maccabees_1,
+	73: bible.Maccabees_2,
+	74: bible.DanielGreek,
+	75: bible.EstherGreek,
+	76: bible.Manasseh,
+	77: bible.Maccabees_3,
+	78: bible.Maccabees_4,
+	79: bible.LetterJeremiah,
+	80: bible.Esdras_1,
+	81: bible.Esdras_2,
+	82: bible.Odes,
+	83: bible.PsalmsSolomon,
+	84: bible.Laodiceans,
+	85: bible.Enoch,
+}
+
+// zefaniaBook returns the book of a <BIBLEBOOK>, by its number or else by
+// its name.
+func zefaniaBook(e xml.StartElement) (bible.Book, error) {
+	bnumber := attr(e, "bnumber")
+	if n, err := strconv.Atoi(bnumber); err == nil {
+		if n >= 1 && n <= 66 {
+			return bible.Book(n - 1), nil
+		}
+		if book, ok := zefaniaBooks[n]; ok {
+			return book, nil
+		}
+	}
+	for _, name := range []string{attr(e, "bsname"), attr(e, "bname")} {
+		if book, ok := bible.LookupBook(name); ok && name != "" {
+			return book, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown book %q (%s)", bnumber, attr(e, "bname"))
+}
+
+// Zefania STYLE fs values and the USFM character styles they become.
+var zefaniaStyles = map[string]string{
+	"italic":      "it",
+	"bold":        "bd",
+	"emphasis":    "em",
+	"small-caps":  "sc",
+	"super":       "sup",
+	"divinename":  "nd",
+	"underline":   "em",
+	"illuminated": "it",
+}
+
+/**
+ * ParseZefania reads the verses of a Zefania XML bible
+ * (XMLBIBLE/BIBLEBOOK/CHAPTER/VERS). Chapter captions become headings,
+ * NOTE elements footnotes and XREF elements cross references. Strong's
+ * numbers (<gr str="7225">) are recorded for the words they mark, taking
+ * the H or G prefix from the testament when the number has none.
+ * language is that of the bible, whose book names XREF targets may use.
+ */
+func ParseZefania(r io.Reader, language string) ([]Verse, error) {
+	p := &usfmParser{}
+	return p.parseXML(xml.NewDecoder(r), func(e xml.StartElement) (func() error, bool, error) {
+		return p.zefaniaElement(e, language)
+	})
+}
+
+func (p *usfmParser) zefaniaElement(e xml.StartElement, language string) (func() error, bool, error) {
+	switch strings.ToLower(e.Name.Local) {
+	case "information":
+		return nil, true, nil
+
+	case "biblebook":
+		book, err := zefaniaBook(e)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := p.token(usfmToken{marker: "id"}); err != nil {
+			return nil, false, err
+		}
+		return nil, false, p.token(usfmToken{text: book.Code() + " "})
+
+	case "chapter":
+		if err := p.token(usfmToken{marker: "c"}); err != nil {
+			return nil, false, err
+		}
+		return nil, false, p.token(usfmToken{text: attr(e, "cnumber") + " "})
+
+	case "vers":
+		number := attr(e, "vnumber")
+		if end := attr(e, "aix"); end != "" && end != number {
+			// Bridged verses: <VERS vnumber="1" aix="2">
+			number += "-" + end
+		}
+		if err := p.token(usfmToken{marker: "v"}); err != nil {
+			return nil, false, err
+		}
+		if err := p.token(usfmToken{text: number + " "}); err != nil {
+			return nil, false, err
+		}
+		return nil, false, nil
+
+	case "caption":
+		if err := p.token(usfmToken{marker: "s"}); err != nil {
+			return nil, false, err
+		}
+		return func() error {
+			p.heading = nil
+			return nil
+		}, false, nil
+
+	case "note":
+		if p.current == nil {
+			return nil, true, nil
+		}
+		if err := p.token(usfmToken{marker: "f"}); err != nil {
+			return nil, false, err
+		}
+		if err := p.token(usfmToken{text: "+ "}); err != nil {
+			return nil, false, err
+		}
+		return p.closeStyle("f"), false, nil
+
+	case "xref":
+		// <XREF fscope="Joh 1,1; Heb 1,10"/>
+		if p.current == nil || attr(e, "fscope") == "" {
+			return nil, false, nil
+		}
+		if err := p.token(usfmToken{marker: "x"}); err != nil {
+			return nil, false, err
+		}
+		if err := p.token(usfmToken{text: "- "}); err != nil {
+			return nil, false, err
+		}
+		p.note.References = zefaniaScope(attr(e, "fscope"), language)
+		if err := p.token(usfmToken{text: attr(e, "fscope")}); err != nil {
+			return nil, false, err
+		}
+		return nil, false, p.token(usfmToken{marker: "x", closing: true})
+
+	case "style":
+		if marker, ok := zefaniaStyles[strings.ToLower(attr(e, "fs"))]; ok {
+			return p.openStyle(marker)
+		}
+
+	case "br":
+		if attr(e, "art") == "x-p" {
+			return nil, false, p.token(usfmToken{marker: "p"})
+		}
+		return nil, false, p.token(usfmToken{text: " "})
+
+	case "gr", "gram":
+		return p.wordElement(Word{
+			Strong: p.zefaniaStrong(attr(e, "str")),
+			Morph:  attr(e, "rmac"),
+		})
+	}
+	return nil, false, nil
+}
+
+/**
+ * zefaniaScope reads the targets of an XREF. Zefania writes them the German
+ * way whatever the language of the bible ("1Mo 1,1; Heb 1,10"), so they are
+ * read with commas between chapter and verse, then in the bible's language
+ * for what that leaves unread. Targets neither can read are dropped.
+ */
+func zefaniaScope(fscope string, language string) []bible.BibleReference {
+	var refs []bible.BibleReference
+	for _, target := range strings.Split(fscope, ";") {
+		if strings.TrimSpace(target) == "" {
+			continue
+		}
+		for _, locale := range []string{"de", language} {
+			if parsed, err := bible.ParseReferenceListLocale(target, locale); err == nil {
+				refs = append(refs, parsed...)
+				break
+			}
+		}
+	}
+	return refs
+}
+
+// zefaniaStrong prefixes bare Strong's numbers with H or G, depending on
+// the testament of the current book.
+func (p *usfmParser) zefaniaStrong(str string) string {
+	var strongs []string
+	for _, strong := range strings.Fields(str) {
+		if strong[0] >= '0' && strong[0] <= '9' {
+			prefix := "G"
+			if p.book < bible.Matthew {
+				prefix = "H"
+			}
+			strong = prefix + strong
+		}
+		strongs = append(strongs, normalizeStrong(strong))
+	}
+	return strings.Join(strongs, " ")
+}
@@ -0,0 +1,46 @@
+package sources
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	bible "bibleapp.server/pkg/bible_parser"
+)
+
+func verseRef(book bible.Book, chapter, startVerse, endVerse uint) bible.BibleReference {
+	return bible.BibleReference{Book: book, StartChapter: chapter, StartVerse: startVerse,
+		EndChapter: chapter, EndVerse: endVerse, Granularity: bible.GRANULARITY_VERSE}
+}
+
+func TestParseZefaniaCrossReferences(t *testing.T) {
+	tests := []struct {
+		fscope   string
+		language string
+		want     []bible.BibleReference
+	}{
+		{"1Mo 1,1; Heb 1,10", "GER", []bible.BibleReference{verseRef(bible.Genesis, 1, 1, 1), verseRef(bible.Hebrews, 1, 10, 10)}},
+		{"1Mo 1,1; Heb 1,10", "ENG", []bible.BibleReference{verseRef(bible.Genesis, 1, 1, 1), verseRef(bible.Hebrews, 1, 10, 10)}},
+		{"Joh 1,1; Heb 1,10", "GER", []bible.BibleReference{verseRef(bible.John, 1, 1, 1), verseRef(bible.Hebrews, 1, 10, 10)}},
+		{"Joh 1,1-3", "", []bible.BibleReference{verseRef(bible.John, 1, 1, 3)}},
+		{"Gen 1:1; John 1:1", "ENG", []bible.BibleReference{verseRef(bible.Genesis, 1, 1, 1), verseRef(bible.John, 1, 1, 1)}},
+		{"Ps 23,1; nowhere 1,1", "GER", []bible.BibleReference{verseRef(bible.Psalms, 23, 1, 1)}},
+	}
+	for _, tt := range tests {
+		xml := `<XMLBIBLE><BIBLEBOOK bnumber="43"><CHAPTER cnumber="1">` +
+			`<VERS vnumber="1">In the beginning was the Word<XREF fscope="` + tt.fscope + `"/></VERS>` +
+			`</CHAPTER></BIBLEBOOK></XMLBIBLE>`
+		verses, err := ParseZefania(strings.NewReader(xml), tt.language)
+		if err != nil {
+			t.Errorf("%q: %v", tt.fscope, err)
+			continue
+		}
+		if len(verses) != 1 || len(verses[0].Notes) != 1 {
+			t.Errorf("%q: verses %+v, want one verse with one note", tt.fscope, verses)
+			continue
+		}
+		if got := verses[0].Notes[0].References; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q (%s) = %+v, want %+v", tt.fscope, tt.language, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package verse
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// BookSuggestion is a book the user may have meant by a name we could not
+// resolve. Distance is the edit distance between the input and Name, or 0 if
+// the input is the start of several books' names ("Phi").
+type BookSuggestion struct {
+	Book     Book   `json:"book"`
+	Name     string `json:"name"`
+	Distance int    `json:"distance"`
+}
+
+// Suggestions are limited to this many books.
+const maxSuggestions = 5
+
+// bookLookup resolves book names by searching languages in order.
+type bookLookup struct {
+	order []*BookNames
+	// Also accept the start of a single book's name: "Philem", "Deuter"
+	prefix bool
+	// A comma separates chapter from verse ("1. Mose 1,1") rather than the
+	// items of a list
+	commaVerses bool
+}
+
+// parserLookup is how references typed by users resolve books.
+func parserLookup(locale string) bookLookup {
+	return bookLookup{order: lookupOrder(locale), prefix: true, commaVerses: commaVerseLocale(locale)}
+}
+
+func (l bookLookup) find(name string) (Book, bool) {
+	if book, ok := lookupBookIn(l.order, name); ok {
+		return book, true
+	}
+	if !l.prefix {
+		return 0, false
+	}
+
+	// The first language with names starting with the input decides.
+	for _, names := range l.order {
+		if books := names.prefixMatches(name); len(books) == 1 {
+			return books[0], true
+		} else if len(books) > 1 {
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// prefixMatches returns the books with a name starting with name.
+func (n *BookNames) prefixMatches(name string) []Book {
+	key := normalizeBookName(name)
+	if utf8.RuneCountInString(key) < 2 {
+		return nil
+	}
+
+	seen := map[Book]bool{}
+	var books []Book
+	for k, book := range n.index {
+		if strings.HasPrefix(k, key) && !seen[book] {
+			seen[book] = true
+			books = append(books, book)
+		}
+	}
+	sort.Slice(books, func(i, j int) bool { return books[i] < books[j] })
+	return books
+}
+
+/**
+ * Suggest ranks the books whose names are closest to name, nearest first.
+ * Names within a third of their length in edits ("Genisis", "Phillipians")
+ * are suggested, as are all the books an ambiguous prefix could mean.
+ */
+func (n *BookNames) Suggest(name string) []BookSuggestion {
+	return bookLookup{order: []*BookNames{n}}.suggest(name)
+}
+
+// SuggestBooks is Suggest in the language of locale and in English, whose
+// names include the USFM codes and OSIS identifiers.
+func SuggestBooks(name string, locale string) []BookSuggestion {
+	return parserLookup(locale).suggest(name)
+}
+
+/**
+ * suggest ranks the books close to name in the first language of the lookup
+ * and in English. The other languages it reads names in would only add
+ * noise: German "Hi" (Hiob) is one edit away from "Phi".
+ */
+func (l bookLookup) suggest(name string) []BookSuggestion {
+	key := normalizeBookName(name)
+	if key == "" {
+		return nil
+	}
+	limit := utf8.RuneCountInString(key) / 3
+	if limit < 1 {
+		limit = 1
+	}
+
+	best := map[Book]BookSuggestion{}
+	consider := func(s BookSuggestion) {
+		if current, ok := best[s.Book]; !ok || s.Distance < current.Distance {
+			best[s.Book] = s
+		}
+	}
+
+	order := l.order
+	if len(order) > 1 {
+		order = order[:1]
+		if order[0] != EnglishBookNames {
+			order = append(order, EnglishBookNames)
+		}
+	}
+	for _, names := range order {
+		for _, book := range names.prefixMatches(name) {
+			consider(BookSuggestion{Book: book, Name: names.Name(book), Distance: 0})
+		}
+		for k, book := range names.index {
+			if d := editDistance(key, k); d <= limit {
+				consider(BookSuggestion{Book: book, Name: names.Name(book), Distance: d})
+			}
+		}
+	}
+
+	suggestions := make([]BookSuggestion, 0, len(best))
+	for _, s := range best {
+		suggestions = append(suggestions, s)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Distance != suggestions[j].Distance {
+			return suggestions[i].Distance < suggestions[j].Distance
+		}
+		return suggestions[i].Book < suggestions[j].Book
+	})
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions
+}
+
+// editDistance is the Levenshtein distance between a and b, in runes.
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a int, b int, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
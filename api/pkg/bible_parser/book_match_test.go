@@ -0,0 +1,53 @@
+package verse
+
+import "testing"
+
+func suggested(suggestions []BookSuggestion, book Book) bool {
+	for _, s := range suggestions {
+		if s.Book == book {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSuggestBooks(t *testing.T) {
+	tests := []struct {
+		name    string
+		locale  string
+		want    []Book
+		notWant []Book
+	}{
+		{"Phi", "en", []Book{Philippians, Philemon}, []Book{Job}},
+		{"Phi", "es", []Book{Philippians, Philemon}, []Book{Job}},
+		{"Genisis", "en", []Book{Genesis}, nil},
+		{"Phillipians", "en", []Book{Philippians}, nil},
+		{"Hiop", "de", []Book{Job}, nil},
+		{"Hiop", "en", nil, []Book{Job}},
+		// English and the USFM codes are suggested whatever the locale
+		{"Genisis", "de", []Book{Genesis}, nil},
+		{"PHX", "de", []Book{Philippians}, nil},
+	}
+	for _, tt := range tests {
+		got := SuggestBooks(tt.name, tt.locale)
+		for _, book := range tt.want {
+			if !suggested(got, book) {
+				t.Errorf("SuggestBooks(%q, %s) = %+v, want %v", tt.name, tt.locale, got, book)
+			}
+		}
+		for _, book := range tt.notWant {
+			if suggested(got, book) {
+				t.Errorf("SuggestBooks(%q, %s) = %+v, want no %v", tt.name, tt.locale, got, book)
+			}
+		}
+	}
+
+	_, err := NormalizeBibleReference("Phi 1:1")
+	re, ok := err.(*ReferenceError)
+	if !ok {
+		t.Fatalf("Phi 1:1: error %v, want a *ReferenceError", err)
+	}
+	if suggested(re.Suggestions, Job) {
+		t.Errorf("Phi 1:1 suggests Job: %+v", re.Suggestions)
+	}
+}
@@ -0,0 +1,319 @@
+package verse
+
+import (
+	"strings"
+	"unicode"
+)
+
+// BookName is how one language names a book.
+type BookName struct {
+	Book         Book
+	Name         string
+	Abbreviation string
+	Aliases      []string
+}
+
+/**
+ * BookNames holds the names of the books in one language, used both to
+ * display books and to recognize them in user input. Input matching is
+ * case-insensitive and ignores spaces, periods and (Latin) accents, so
+ * "Génesis", "genesis" and "GEN." all match.
+ */
+type BookNames struct {
+	Language string
+	names    map[Book]BookName
+	index    map[string]Book
+	// exact is keyed without folding accents, so that in Portuguese "Jó"
+	// is Job while "Jo" is João.
+	exact map[string]Book
+	// numbered holds the names with digits of their own ("PS2", "Psalm
+	// 151"), keyed with their spacing kept: "Ps 2" is a chapter of Psalms.
+	numbered map[string]Book
+}
+
+func newBookNames(language string, entries []BookName) *BookNames {
+	names := &BookNames{
+		Language: language,
+		names:    map[Book]BookName{},
+		index:    map[string]Book{},
+		exact:    map[string]Book{},
+		numbered: map[string]Book{},
+	}
+	for _, entry := range entries {
+		names.names[entry.Book] = entry
+		names.add(entry.Name, entry.Book)
+		names.add(entry.Abbreviation, entry.Book)
+		for _, alias := range entry.Aliases {
+			names.add(alias, entry.Book)
+		}
+	}
+	return names
+}
+
+func (n *BookNames) add(name string, book Book) {
+	key := normalizeBookName(name)
+	if key == "" {
+		return
+	}
+
+	exact := stripBookName(strings.ToLower(name))
+	if _, exists := n.exact[exact]; !exists {
+		n.exact[exact] = book
+	}
+	// The first name wins the folded key, except that an unaccented name
+	// takes it from an accented one ("Jo" over "Jó")
+	if _, exists := n.index[key]; !exists || (exact == key && n.exact[key] == book) {
+		n.index[key] = book
+	}
+	if numberedBookName(name) {
+		if _, exists := n.numbered[spacedBookName(name)]; !exists {
+			n.numbered[spacedBookName(name)] = book
+		}
+	}
+}
+
+// numberedBookName reports whether a digit follows the first letter of name,
+// as in "PS2" or "Psalm 151" but not "1 Sam".
+func numberedBookName(name string) bool {
+	letter := strings.IndexFunc(name, unicode.IsLetter)
+	return letter >= 0 && strings.IndexFunc(name[letter:], unicode.IsDigit) >= 0
+}
+
+// spacedBookName folds a book name like normalizeBookName but keeps one
+// space wherever it had spaces or periods ("Ps. 151" == "ps 151").
+func spacedBookName(name string) string {
+	name = accentFolder.Replace(strings.ToLower(name))
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '.' || r == '_'
+	}), " ")
+}
+
+// Name returns the full name of book, falling back to English.
+func (n *BookNames) Name(book Book) string {
+	if entry, ok := n.names[book]; ok && entry.Name != "" {
+		return entry.Name
+	}
+	if n != EnglishBookNames {
+		return EnglishBookNames.Name(book)
+	}
+	return book.String()
+}
+
+// Abbreviation returns the abbreviated name of book, falling back to
+// English.
+func (n *BookNames) Abbreviation(book Book) string {
+	if entry, ok := n.names[book]; ok && entry.Abbreviation != "" {
+		return entry.Abbreviation
+	}
+	if n != EnglishBookNames {
+		return EnglishBookNames.Abbreviation(book)
+	}
+	return book.OSIS()
+}
+
+/**
+ * Lookup resolves a name or abbreviation in this language to a Book.
+ * Numbered books should be given with a leading digit ("1 Sam", "1. Mose").
+ */
+func (n *BookNames) Lookup(name string) (Book, bool) {
+	if book, ok := n.exact[stripBookName(strings.ToLower(name))]; ok {
+		return book, true
+	}
+	book, ok := n.index[normalizeBookName(name)]
+	return book, ok
+}
+
+// lookupNumbered resolves a name with digits of its own, as it is spaced.
+func (n *BookNames) lookupNumbered(name string) (Book, bool) {
+	book, ok := n.numbered[spacedBookName(name)]
+	return book, ok
+}
+
+// Latin accents that users routinely leave off.
+var accentFolder = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n", "ß", "ss", "ё", "е",
+)
+
+// normalizeBookName lower-cases a book name and strips the spaces, periods
+// and accents that users are inconsistent about ("1 Cor." == "1cor").
+func normalizeBookName(name string) string {
+	return stripBookName(accentFolder.Replace(strings.ToLower(name)))
+}
+
+// stripBookName removes the spaces and periods from a book name.
+func stripBookName(name string) string {
+	var sb strings.Builder
+	for _, r := range name {
+		switch r {
+		case ' ', '\t', '.', '_':
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// englishAbbreviations are the SBL Handbook abbreviations, indexed by Book.
+var englishAbbreviations = []string{
+	"Gen", "Exod", "Lev", "Num", "Deut", "Josh", "Judg", "Ruth", "1 Sam", "2 Sam",
+	"1 Kgs", "2 Kgs", "1 Chr", "2 Chr", "Ezra", "Neh", "Esth", "Job", "Ps", "Prov",
+	"Eccl", "Song", "Isa", "Jer", "Lam", "Ezek", "Dan", "Hos", "Joel", "Amos",
+	"Obad", "Jonah", "Mic", "Nah", "Hab", "Zeph", "Hag", "Zech", "Mal",
+	"Matt", "Mark", "Luke", "John", "Acts", "Rom", "1 Cor", "2 Cor", "Gal", "Eph",
+	"Phil", "Col", "1 Thess", "2 Thess", "1 Tim", "2 Tim", "Titus", "Phlm", "Heb", "Jas",
+	"1 Pet", "2 Pet", "1 John", "2 John", "3 John", "Jude", "Rev",
+	"Tob", "Jdt", "Add Esth", "Wis", "Sir", "Bar", "Ep Jer", "Pr Azar", "Sus", "Bel",
+	"1 Macc", "2 Macc", "3 Macc", "4 Macc", "1 Esd", "2 Esd", "Pr Man", "Ps 151", "Odes", "Pss Sol",
+	"Add Dan", "1 En", "Laod",
+}
+
+// EnglishBookNames also accepts the USFM codes and OSIS identifiers.
+var EnglishBookNames = func() *BookNames {
+	entries := make([]BookName, len(bookCodes))
+	for i, code := range bookCodes {
+		book := Book(i)
+		entries[i] = BookName{
+			Book:         book,
+			Name:         Books[code],
+			Abbreviation: englishAbbreviations[i],
+			Aliases:      append([]string{code, bookOSIS[i], book.String()}, bookAliases[book]...),
+		}
+	}
+	names := newBookNames("en", entries)
+	// Codes and identifiers name their own book, whatever the aliases of an
+	// earlier one say ("PSS" is the Psalms of Solomon)
+	for i, code := range bookCodes {
+		for _, id := range []string{bookOSIS[i], code} {
+			key := normalizeBookName(id)
+			names.index[key], names.exact[key] = Book(i), Book(i)
+			if numberedBookName(id) {
+				names.numbered[spacedBookName(id)] = Book(i)
+			}
+		}
+	}
+	return names
+}()
+
+/*
+ * bookNamesByLanguage holds every language we have book names for. Input is
+ * matched against English first and then the others in this order, unless a
+ * locale says otherwise.
+ */
+var bookNamesByLanguage = map[string]*BookNames{
+	"en": EnglishBookNames,
+	"es": spanishBookNames,
+	"fr": frenchBookNames,
+	"de": germanBookNames,
+	"pt": portugueseBookNames,
+	"ru": russianBookNames,
+}
+
+var bookNamesOrder = []string{"en", "es", "fr", "de", "pt", "ru"}
+
+/*
+ * languageCodes maps the other ways locales and version languages name a
+ * language (ISO 639-2/3 codes, English and native names) onto the ISO 639-1
+ * code we key book names by.
+ */
+var languageCodes = map[string]string{
+	"eng": "en", "english": "en",
+	"spa": "es", "spanish": "es", "español": "es", "espanol": "es", "castellano": "es",
+	"fra": "fr", "fre": "fr", "french": "fr", "français": "fr", "francais": "fr",
+	"deu": "de", "ger": "de", "german": "de", "deutsch": "de",
+	"por": "pt", "portuguese": "pt", "português": "pt", "portugues": "pt",
+	"rus": "ru", "russian": "ru", "русский": "ru",
+}
+
+// commaVerseLocale reports whether references of the locale separate
+// chapter and verse with a comma, as German ones do ("Joh 3,16").
+func commaVerseLocale(locale string) bool {
+	return languageCode(locale) == "de"
+}
+
+// languageCode reduces a locale ("es-MX", "pt_BR") or a version's language
+// ("spanish", "deu") to an ISO 639-1 code, or "" if it is not recognized.
+func languageCode(locale string) string {
+	lang := strings.ToLower(strings.TrimSpace(locale))
+	if idx := strings.IndexAny(lang, "-_"); idx >= 0 {
+		lang = lang[:idx]
+	}
+	if _, ok := bookNamesByLanguage[lang]; ok {
+		return lang
+	}
+	return languageCodes[lang]
+}
+
+/**
+ * NamesForLocale returns the book names for a locale ("es", "pt-BR", "de_DE")
+ * or for the language of a version ("spanish", "rus", "Deutsch"). Unknown
+ * languages fall back to English.
+ */
+func NamesForLocale(locale string) *BookNames {
+	if names, ok := bookNamesByLanguage[languageCode(locale)]; ok {
+		return names
+	}
+	return EnglishBookNames
+}
+
+// Languages returns the ISO 639-1 codes of every language with book names.
+func Languages() []string {
+	return append([]string(nil), bookNamesOrder...)
+}
+
+// lookupOrder returns the book names to search for a locale: the locale's
+// language first, then every other language.
+func lookupOrder(locale string) []*BookNames {
+	first := languageCode(locale)
+
+	var order []*BookNames
+	if names, ok := bookNamesByLanguage[first]; ok {
+		order = append(order, names)
+	}
+	for _, lang := range bookNamesOrder {
+		if lang != first {
+			order = append(order, bookNamesByLanguage[lang])
+		}
+	}
+	return order
+}
+
+func lookupBookIn(order []*BookNames, name string) (Book, bool) {
+	for _, names := range order {
+		if book, ok := names.Lookup(name); ok {
+			return book, true
+		}
+	}
+	return 0, false
+}
+
+// lookupNumberedIn is lookupBookIn for names with digits of their own.
+func lookupNumberedIn(order []*BookNames, name string) (Book, bool) {
+	for _, names := range order {
+		if book, ok := names.lookupNumbered(name); ok {
+			return book, true
+		}
+	}
+	return 0, false
+}
+
+/**
+ * LookupBook resolves a book name, abbreviation, USFM code or OSIS identifier
+ * in any supported language to a Book, preferring English when a name means
+ * different books in different languages. Numbered books should be given with
+ * a leading digit ("1 Sam"), which is what NormalizeBibleReference reduces
+ * other ordinals to.
+ */
+func LookupBook(name string) (Book, bool) {
+	return lookupBookIn(lookupOrder("en"), name)
+}
+
+// LookupBookLocale is LookupBook preferring the names of the given locale,
+// so that "Jn" is Jonah in Portuguese but John in Spanish.
+func LookupBookLocale(name string, locale string) (Book, bool) {
+	return lookupBookIn(lookupOrder(locale), name)
+}
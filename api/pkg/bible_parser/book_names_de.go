@@ -0,0 +1,79 @@
+package verse
+
+// German book names (Luther).
+var germanBookNames = newBookNames("de", []BookName{
+	{Genesis, "1. Mose", "1Mo", []string{"Genesis", "1 Mos"}},
+	{Exodus, "2. Mose", "2Mo", []string{"Exodus", "2 Mos"}},
+	{Leviticus, "3. Mose", "3Mo", []string{"Levitikus", "3 Mos"}},
+	{Numbers, "4. Mose", "4Mo", []string{"Numeri", "4 Mos"}},
+	{Deuteronomy, "5. Mose", "5Mo", []string{"Deuteronomium", "5 Mos"}},
+	{Joshua, "Josua", "Jos", nil},
+	{Judges, "Richter", "Ri", nil},
+	{Ruth, "Rut", "Rut", []string{"Ruth"}},
+	{Samuel_1, "1. Samuel", "1Sam", nil},
+	{Samuel_2, "2. Samuel", "2Sam", nil},
+	{Kings_1, "1. Könige", "1Kön", []string{"1 Kö"}},
+	{Kings_2, "2. Könige", "2Kön", []string{"2 Kö"}},
+	{Chronicles_1, "1. Chronik", "1Chr", nil},
+	{Chronicles_2, "2. Chronik", "2Chr", nil},
+	{Ezra, "Esra", "Esr", nil},
+	{Nehemiah, "Nehemia", "Neh", nil},
+	{Esther, "Ester", "Est", []string{"Esther"}},
+	{Job, "Hiob", "Hi", []string{"Ijob", "Job"}},
+	{Psalms, "Psalmen", "Ps", []string{"Psalm"}},
+	{Proverbs, "Sprüche", "Spr", nil},
+	{Ecclesiastes, "Prediger", "Pred", []string{"Kohelet", "Koh"}},
+	{Song, "Hoheslied", "Hld", []string{"Hohelied"}},
+	{Isaiah, "Jesaja", "Jes", nil},
+	{Jeremiah, "Jeremia", "Jer", nil},
+	{Lamentations, "Klagelieder", "Klgl", []string{"Klg"}},
+	{Ezekiel, "Hesekiel", "Hes", []string{"Ezechiel", "Ez"}},
+	{Daniel, "Daniel", "Dan", nil},
+	{Hosea, "Hosea", "Hos", nil},
+	{Joel, "Joel", "Joel", nil},
+	{Amos, "Amos", "Am", nil},
+	{Obadiah, "Obadja", "Obd", nil},
+	{Jonah, "Jona", "Jona", nil},
+	{Micah, "Micha", "Mi", nil},
+	{Nahum, "Nahum", "Nah", nil},
+	{Habakkuk, "Habakuk", "Hab", nil},
+	{Zephaniah, "Zefanja", "Zef", []string{"Zephanja"}},
+	{Haggai, "Haggai", "Hag", nil},
+	{Zechariah, "Sacharja", "Sach", nil},
+	{Malachi, "Maleachi", "Mal", nil},
+	{Matthew, "Matthäus", "Mt", nil},
+	{Mark, "Markus", "Mk", nil},
+	{Luke, "Lukas", "Lk", nil},
+	{John, "Johannes", "Joh", nil},
+	{Acts, "Apostelgeschichte", "Apg", nil},
+	{Romans, "Römer", "Röm", nil},
+	{Corinthians_1, "1. Korinther", "1Kor", nil},
+	{Corinthians_2, "2. Korinther", "2Kor", nil},
+	{Galatians, "Galater", "Gal", nil},
+	{Ephesians, "Epheser", "Eph", nil},
+	{Philippians, "Philipper", "Phil", nil},
+	{Colossians, "Kolosser", "Kol", nil},
+	{Thessalonians_1, "1. Thessalonicher", "1Thess", nil},
+	{Thessalonians_2, "2. Thessalonicher", "2Thess", nil},
+	{Timothy_1, "1. Timotheus", "1Tim", nil},
+	{Timothy_2, "2. Timotheus", "2Tim", nil},
+	{Titus, "Titus", "Tit", nil},
+	{Philemon, "Philemon", "Phlm", nil},
+	{Hebrews, "Hebräer", "Hebr", nil},
+	{James, "Jakobus", "Jak", nil},
+	{Peter_1, "1. Petrus", "1Petr", nil},
+	{Peter_2, "2. Petrus", "2Petr", nil},
+	{John_1, "1. Johannes", "1Joh", nil},
+	{John_2, "2. Johannes", "2Joh", nil},
+	{John_3, "3. Johannes", "3Joh", nil},
+	{Jude, "Judas", "Jud", nil},
+	{Revelation, "Offenbarung", "Offb", []string{"Apokalypse"}},
+	{Tobit, "Tobit", "Tob", []string{"Tobias"}},
+	{Judith, "Judit", "Jdt", []string{"Judith"}},
+	{Wisdom, "Weisheit", "Weish", nil},
+	{Sirach, "Jesus Sirach", "Sir", []string{"Sirach"}},
+	{Baruch, "Baruch", "Bar", nil},
+	{Maccabees_1, "1. Makkabäer", "1Makk", nil},
+	{Maccabees_2, "2. Makkabäer", "2Makk", nil},
+	{Manasseh, "Gebet des Manasse", "GebMan", nil},
+})
@@ -0,0 +1,79 @@
+package verse
+
+// Spanish book names (Reina-Valera).
+var spanishBookNames = newBookNames("es", []BookName{
+	{Genesis, "Génesis", "Gn", []string{"Gén", "Ge"}},
+	{Exodus, "Éxodo", "Ex", []string{"Éx", "Exo"}},
+	{Leviticus, "Levítico", "Lv", []string{"Lev"}},
+	{Numbers, "Números", "Nm", []string{"Núm", "Nu"}},
+	{Deuteronomy, "Deuteronomio", "Dt", []string{"Deut"}},
+	{Joshua, "Josué", "Jos", nil},
+	{Judges, "Jueces", "Jue", []string{"Jc"}},
+	{Ruth, "Rut", "Rt", nil},
+	{Samuel_1, "1 Samuel", "1 S", []string{"1 Sam", "1 Sa"}},
+	{Samuel_2, "2 Samuel", "2 S", []string{"2 Sam", "2 Sa"}},
+	{Kings_1, "1 Reyes", "1 R", []string{"1 Re", "1 Rey"}},
+	{Kings_2, "2 Reyes", "2 R", []string{"2 Re", "2 Rey"}},
+	{Chronicles_1, "1 Crónicas", "1 Cr", []string{"1 Cró", "1 Cro"}},
+	{Chronicles_2, "2 Crónicas", "2 Cr", []string{"2 Cró", "2 Cro"}},
+	{Ezra, "Esdras", "Esd", nil},
+	{Nehemiah, "Nehemías", "Neh", nil},
+	{Esther, "Ester", "Est", nil},
+	{Job, "Job", "Job", nil},
+	{Psalms, "Salmos", "Sal", []string{"Salmo", "Sl"}},
+	{Proverbs, "Proverbios", "Pr", []string{"Prov", "Pro"}},
+	{Ecclesiastes, "Eclesiastés", "Ec", []string{"Ecl", "Qo"}},
+	{Song, "Cantares", "Cnt", []string{"Cantar de los Cantares", "Cant", "Ct"}},
+	{Isaiah, "Isaías", "Is", []string{"Isa"}},
+	{Jeremiah, "Jeremías", "Jer", []string{"Jr"}},
+	{Lamentations, "Lamentaciones", "Lm", []string{"Lam"}},
+	{Ezekiel, "Ezequiel", "Ez", []string{"Eze"}},
+	{Daniel, "Daniel", "Dn", []string{"Dan"}},
+	{Hosea, "Oseas", "Os", nil},
+	{Joel, "Joel", "Jl", nil},
+	{Amos, "Amós", "Am", nil},
+	{Obadiah, "Abdías", "Abd", nil},
+	{Jonah, "Jonás", "Jon", nil},
+	{Micah, "Miqueas", "Miq", nil},
+	{Nahum, "Nahúm", "Nah", nil},
+	{Habakkuk, "Habacuc", "Hab", nil},
+	{Zephaniah, "Sofonías", "Sof", nil},
+	{Haggai, "Hageo", "Hag", nil},
+	{Zechariah, "Zacarías", "Zac", nil},
+	{Malachi, "Malaquías", "Mal", nil},
+	{Matthew, "Mateo", "Mt", []string{"Mat"}},
+	{Mark, "Marcos", "Mr", []string{"Mc", "Mar"}},
+	{Luke, "Lucas", "Lc", []string{"Luc"}},
+	{John, "Juan", "Jn", nil},
+	{Acts, "Hechos", "Hch", []string{"Hech"}},
+	{Romans, "Romanos", "Ro", []string{"Rom"}},
+	{Corinthians_1, "1 Corintios", "1 Co", []string{"1 Cor"}},
+	{Corinthians_2, "2 Corintios", "2 Co", []string{"2 Cor"}},
+	{Galatians, "Gálatas", "Gá", []string{"Gál", "Gal"}},
+	{Ephesians, "Efesios", "Ef", []string{"Efe"}},
+	{Philippians, "Filipenses", "Flp", []string{"Fil"}},
+	{Colossians, "Colosenses", "Col", nil},
+	{Thessalonians_1, "1 Tesalonicenses", "1 Ts", []string{"1 Tes"}},
+	{Thessalonians_2, "2 Tesalonicenses", "2 Ts", []string{"2 Tes"}},
+	{Timothy_1, "1 Timoteo", "1 Ti", []string{"1 Tim"}},
+	{Timothy_2, "2 Timoteo", "2 Ti", []string{"2 Tim"}},
+	{Titus, "Tito", "Tit", nil},
+	{Philemon, "Filemón", "Flm", nil},
+	{Hebrews, "Hebreos", "He", []string{"Heb"}},
+	{James, "Santiago", "Stg", []string{"Sant"}},
+	{Peter_1, "1 Pedro", "1 P", []string{"1 Pe", "1 Ped"}},
+	{Peter_2, "2 Pedro", "2 P", []string{"2 Pe", "2 Ped"}},
+	{John_1, "1 Juan", "1 Jn", nil},
+	{John_2, "2 Juan", "2 Jn", nil},
+	{John_3, "3 Juan", "3 Jn", nil},
+	{Jude, "Judas", "Jud", nil},
+	{Revelation, "Apocalipsis", "Ap", []string{"Apoc"}},
+	{Tobit, "Tobías", "Tb", []string{"Tob"}},
+	{Judith, "Judit", "Jdt", nil},
+	{Wisdom, "Sabiduría", "Sab", []string{"Sb"}},
+	{Sirach, "Eclesiástico", "Eclo", []string{"Sirácida", "Si"}},
+	{Baruch, "Baruc", "Bar", nil},
+	{LetterJeremiah, "Carta de Jeremías", "CtaJer", nil},
+	{Maccabees_1, "1 Macabeos", "1 Mac", []string{"1 M"}},
+	{Maccabees_2, "2 Macabeos", "2 Mac", []string{"2 M"}},
+})
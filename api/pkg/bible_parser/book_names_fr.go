@@ -0,0 +1,79 @@
+package verse
+
+// French book names (Louis Segond).
+var frenchBookNames = newBookNames("fr", []BookName{
+	{Genesis, "Genèse", "Gn", []string{"Gen"}},
+	{Exodus, "Exode", "Ex", []string{"Exo"}},
+	{Leviticus, "Lévitique", "Lv", []string{"Lév"}},
+	{Numbers, "Nombres", "Nb", []string{"Nom"}},
+	{Deuteronomy, "Deutéronome", "Dt", []string{"Deut"}},
+	{Joshua, "Josué", "Jos", nil},
+	{Judges, "Juges", "Jg", []string{"Jug"}},
+	{Ruth, "Ruth", "Rt", nil},
+	{Samuel_1, "1 Samuel", "1 S", []string{"1 Sam"}},
+	{Samuel_2, "2 Samuel", "2 S", []string{"2 Sam"}},
+	{Kings_1, "1 Rois", "1 R", nil},
+	{Kings_2, "2 Rois", "2 R", nil},
+	{Chronicles_1, "1 Chroniques", "1 Ch", []string{"1 Chr"}},
+	{Chronicles_2, "2 Chroniques", "2 Ch", []string{"2 Chr"}},
+	{Ezra, "Esdras", "Esd", nil},
+	{Nehemiah, "Néhémie", "Né", []string{"Neh"}},
+	{Esther, "Esther", "Est", nil},
+	{Job, "Job", "Jb", nil},
+	{Psalms, "Psaumes", "Ps", []string{"Psaume"}},
+	{Proverbs, "Proverbes", "Pr", []string{"Prov"}},
+	{Ecclesiastes, "Ecclésiaste", "Ec", []string{"Qohélet", "Qo"}},
+	{Song, "Cantique des cantiques", "Ct", []string{"Cantique", "Cant"}},
+	{Isaiah, "Ésaïe", "És", []string{"Isaïe", "Is"}},
+	{Jeremiah, "Jérémie", "Jr", []string{"Jér"}},
+	{Lamentations, "Lamentations", "Lm", []string{"Lam"}},
+	{Ezekiel, "Ézéchiel", "Éz", []string{"Ézé"}},
+	{Daniel, "Daniel", "Dn", []string{"Dan"}},
+	{Hosea, "Osée", "Os", nil},
+	{Joel, "Joël", "Jl", nil},
+	{Amos, "Amos", "Am", nil},
+	{Obadiah, "Abdias", "Ab", []string{"Abd"}},
+	{Jonah, "Jonas", "Jon", nil},
+	{Micah, "Michée", "Mi", []string{"Mic"}},
+	{Nahum, "Nahum", "Na", nil},
+	{Habakkuk, "Habacuc", "Ha", []string{"Hab"}},
+	{Zephaniah, "Sophonie", "So", []string{"Soph"}},
+	{Haggai, "Aggée", "Ag", nil},
+	{Zechariah, "Zacharie", "Za", []string{"Zach"}},
+	{Malachi, "Malachie", "Ml", []string{"Mal"}},
+	{Matthew, "Matthieu", "Mt", []string{"Matt"}},
+	{Mark, "Marc", "Mc", nil},
+	{Luke, "Luc", "Lc", nil},
+	{John, "Jean", "Jn", nil},
+	{Acts, "Actes", "Ac", []string{"Act"}},
+	{Romans, "Romains", "Rm", []string{"Rom"}},
+	{Corinthians_1, "1 Corinthiens", "1 Co", []string{"1 Cor"}},
+	{Corinthians_2, "2 Corinthiens", "2 Co", []string{"2 Cor"}},
+	{Galatians, "Galates", "Ga", []string{"Gal"}},
+	{Ephesians, "Éphésiens", "Ép", []string{"Éph"}},
+	{Philippians, "Philippiens", "Ph", []string{"Phil"}},
+	{Colossians, "Colossiens", "Col", nil},
+	{Thessalonians_1, "1 Thessaloniciens", "1 Th", []string{"1 Thess"}},
+	{Thessalonians_2, "2 Thessaloniciens", "2 Th", []string{"2 Thess"}},
+	{Timothy_1, "1 Timothée", "1 Tm", []string{"1 Tim"}},
+	{Timothy_2, "2 Timothée", "2 Tm", []string{"2 Tim"}},
+	{Titus, "Tite", "Tt", nil},
+	{Philemon, "Philémon", "Phm", nil},
+	{Hebrews, "Hébreux", "Hé", []string{"Héb"}},
+	{James, "Jacques", "Jc", []string{"Jac"}},
+	{Peter_1, "1 Pierre", "1 P", []string{"1 Pi"}},
+	{Peter_2, "2 Pierre", "2 P", []string{"2 Pi"}},
+	{John_1, "1 Jean", "1 Jn", nil},
+	{John_2, "2 Jean", "2 Jn", nil},
+	{John_3, "3 Jean", "3 Jn", nil},
+	{Jude, "Jude", "Jude", []string{"Jd"}},
+	{Revelation, "Apocalypse", "Ap", []string{"Apoc"}},
+	{Tobit, "Tobie", "Tb", nil},
+	{Judith, "Judith", "Jdt", nil},
+	{Wisdom, "Sagesse", "Sg", []string{"Sag"}},
+	{Sirach, "Siracide", "Si", []string{"Ecclésiastique", "Sir"}},
+	{Baruch, "Baruch", "Ba", nil},
+	{LetterJeremiah, "Lettre de Jérémie", "LJr", nil},
+	{Maccabees_1, "1 Maccabées", "1 M", []string{"1 Mac"}},
+	{Maccabees_2, "2 Maccabées", "2 M", []string{"2 Mac"}},
+})
@@ -0,0 +1,78 @@
+package verse
+
+// Portuguese book names (Almeida).
+var portugueseBookNames = newBookNames("pt", []BookName{
+	{Genesis, "Gênesis", "Gn", []string{"Gên"}},
+	{Exodus, "Êxodo", "Êx", nil},
+	{Leviticus, "Levítico", "Lv", []string{"Lev"}},
+	{Numbers, "Números", "Nm", []string{"Núm"}},
+	{Deuteronomy, "Deuteronômio", "Dt", []string{"Deuteronómio"}},
+	{Joshua, "Josué", "Js", []string{"Jos"}},
+	{Judges, "Juízes", "Jz", []string{"Juí"}},
+	{Ruth, "Rute", "Rt", nil},
+	{Samuel_1, "1 Samuel", "1Sm", nil},
+	{Samuel_2, "2 Samuel", "2Sm", nil},
+	{Kings_1, "1 Reis", "1Rs", nil},
+	{Kings_2, "2 Reis", "2Rs", nil},
+	{Chronicles_1, "1 Crônicas", "1Cr", []string{"1 Crónicas"}},
+	{Chronicles_2, "2 Crônicas", "2Cr", []string{"2 Crónicas"}},
+	{Ezra, "Esdras", "Ed", []string{"Esd"}},
+	{Nehemiah, "Neemias", "Ne", nil},
+	{Esther, "Ester", "Et", nil},
+	{Job, "Jó", "Jó", nil},
+	{Psalms, "Salmos", "Sl", []string{"Salmo"}},
+	{Proverbs, "Provérbios", "Pv", []string{"Prov"}},
+	{Ecclesiastes, "Eclesiastes", "Ec", []string{"Ecl"}},
+	{Song, "Cânticos", "Ct", []string{"Cântico dos Cânticos", "Cantares"}},
+	{Isaiah, "Isaías", "Is", nil},
+	{Jeremiah, "Jeremias", "Jr", nil},
+	{Lamentations, "Lamentações", "Lm", nil},
+	{Ezekiel, "Ezequiel", "Ez", nil},
+	{Daniel, "Daniel", "Dn", nil},
+	{Hosea, "Oseias", "Os", []string{"Oséias"}},
+	{Joel, "Joel", "Jl", nil},
+	{Amos, "Amós", "Am", nil},
+	{Obadiah, "Obadias", "Ob", nil},
+	{Jonah, "Jonas", "Jn", nil},
+	{Micah, "Miqueias", "Mq", []string{"Miquéias"}},
+	{Nahum, "Naum", "Na", nil},
+	{Habakkuk, "Habacuque", "Hc", nil},
+	{Zephaniah, "Sofonias", "Sf", nil},
+	{Haggai, "Ageu", "Ag", nil},
+	{Zechariah, "Zacarias", "Zc", nil},
+	{Malachi, "Malaquias", "Ml", nil},
+	{Matthew, "Mateus", "Mt", nil},
+	{Mark, "Marcos", "Mc", nil},
+	{Luke, "Lucas", "Lc", nil},
+	{John, "João", "Jo", nil},
+	{Acts, "Atos", "At", nil},
+	{Romans, "Romanos", "Rm", nil},
+	{Corinthians_1, "1 Coríntios", "1Co", nil},
+	{Corinthians_2, "2 Coríntios", "2Co", nil},
+	{Galatians, "Gálatas", "Gl", nil},
+	{Ephesians, "Efésios", "Ef", nil},
+	{Philippians, "Filipenses", "Fp", nil},
+	{Colossians, "Colossenses", "Cl", nil},
+	{Thessalonians_1, "1 Tessalonicenses", "1Ts", nil},
+	{Thessalonians_2, "2 Tessalonicenses", "2Ts", nil},
+	{Timothy_1, "1 Timóteo", "1Tm", nil},
+	{Timothy_2, "2 Timóteo", "2Tm", nil},
+	{Titus, "Tito", "Tt", nil},
+	{Philemon, "Filemom", "Fm", []string{"Filemon"}},
+	{Hebrews, "Hebreus", "Hb", nil},
+	{James, "Tiago", "Tg", nil},
+	{Peter_1, "1 Pedro", "1Pe", nil},
+	{Peter_2, "2 Pedro", "2Pe", nil},
+	{John_1, "1 João", "1Jo", nil},
+	{John_2, "2 João", "2Jo", nil},
+	{John_3, "3 João", "3Jo", nil},
+	{Jude, "Judas", "Jd", nil},
+	{Revelation, "Apocalipse", "Ap", nil},
+	{Tobit, "Tobias", "Tb", nil},
+	{Judith, "Judite", "Jt", []string{"Judith"}},
+	{Wisdom, "Sabedoria", "Sb", nil},
+	{Sirach, "Eclesiástico", "Eclo", []string{"Sirácida"}},
+	{Baruch, "Baruc", "Br", nil},
+	{Maccabees_1, "1 Macabeus", "1Mc", nil},
+	{Maccabees_2, "2 Macabeus", "2Mc", nil},
+})
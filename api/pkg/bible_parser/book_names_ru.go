@@ -0,0 +1,86 @@
+package verse
+
+/*
+ * Russian book names (Synodal). The Synodal numbers 1-2 Samuel and 1-2 Kings
+ * together as 1-4 Kingdoms ("Царств"), and 1-2 Esdras as 2-3 Ezra ("Ездры").
+ */
+var russianBookNames = newBookNames("ru", []BookName{
+	{Genesis, "Бытие", "Быт", nil},
+	{Exodus, "Исход", "Исх", nil},
+	{Leviticus, "Левит", "Лев", nil},
+	{Numbers, "Числа", "Чис", nil},
+	{Deuteronomy, "Второзаконие", "Втор", nil},
+	{Joshua, "Иисус Навин", "Нав", []string{"Иисуса Навина", "Навин"}},
+	{Judges, "Судьи", "Суд", nil},
+	{Ruth, "Руфь", "Руф", nil},
+	{Samuel_1, "1 Царств", "1 Цар", nil},
+	{Samuel_2, "2 Царств", "2 Цар", nil},
+	{Kings_1, "3 Царств", "3 Цар", nil},
+	{Kings_2, "4 Царств", "4 Цар", nil},
+	{Chronicles_1, "1 Паралипоменон", "1 Пар", nil},
+	{Chronicles_2, "2 Паралипоменон", "2 Пар", nil},
+	{Ezra, "Ездра", "Езд", []string{"Ездр"}},
+	{Nehemiah, "Неемия", "Неем", nil},
+	{Esther, "Есфирь", "Есф", nil},
+	{Job, "Иов", "Иов", nil},
+	{Psalms, "Псалтирь", "Пс", []string{"Псалом", "Псалмы"}},
+	{Proverbs, "Притчи", "Притч", []string{"Прит"}},
+	{Ecclesiastes, "Екклесиаст", "Еккл", nil},
+	{Song, "Песнь песней", "Песн", nil},
+	{Isaiah, "Исаия", "Ис", nil},
+	{Jeremiah, "Иеремия", "Иер", nil},
+	{Lamentations, "Плач Иеремии", "Плач", nil},
+	{Ezekiel, "Иезекииль", "Иез", nil},
+	{Daniel, "Даниил", "Дан", nil},
+	{Hosea, "Осия", "Ос", nil},
+	{Joel, "Иоиль", "Иоил", nil},
+	{Amos, "Амос", "Ам", nil},
+	{Obadiah, "Авдий", "Авд", nil},
+	{Jonah, "Иона", "Ион", nil},
+	{Micah, "Михей", "Мих", nil},
+	{Nahum, "Наум", "Наум", nil},
+	{Habakkuk, "Аввакум", "Авв", nil},
+	{Zephaniah, "Софония", "Соф", nil},
+	{Haggai, "Аггей", "Агг", nil},
+	{Zechariah, "Захария", "Зах", nil},
+	{Malachi, "Малахия", "Мал", nil},
+	{Matthew, "От Матфея", "Мф", []string{"Матфея", "Матфей", "Мт"}},
+	{Mark, "От Марка", "Мк", []string{"Марка", "Марк"}},
+	{Luke, "От Луки", "Лк", []string{"Луки", "Лука"}},
+	{John, "От Иоанна", "Ин", []string{"Иоанна", "Иоанн"}},
+	{Acts, "Деяния", "Деян", nil},
+	{Romans, "Римлянам", "Рим", nil},
+	{Corinthians_1, "1 Коринфянам", "1 Кор", nil},
+	{Corinthians_2, "2 Коринфянам", "2 Кор", nil},
+	{Galatians, "Галатам", "Гал", nil},
+	{Ephesians, "Ефесянам", "Еф", nil},
+	{Philippians, "Филиппийцам", "Флп", nil},
+	{Colossians, "Колоссянам", "Кол", nil},
+	{Thessalonians_1, "1 Фессалоникийцам", "1 Фес", nil},
+	{Thessalonians_2, "2 Фессалоникийцам", "2 Фес", nil},
+	{Timothy_1, "1 Тимофею", "1 Тим", nil},
+	{Timothy_2, "2 Тимофею", "2 Тим", nil},
+	{Titus, "Титу", "Тит", nil},
+	{Philemon, "Филимону", "Флм", nil},
+	{Hebrews, "Евреям", "Евр", nil},
+	{James, "Иакова", "Иак", nil},
+	{Peter_1, "1 Петра", "1 Пет", nil},
+	{Peter_2, "2 Петра", "2 Пет", nil},
+	{John_1, "1 Иоанна", "1 Ин", nil},
+	{John_2, "2 Иоанна", "2 Ин", nil},
+	{John_3, "3 Иоанна", "3 Ин", nil},
+	{Jude, "Иуды", "Иуд", nil},
+	{Revelation, "Откровение", "Откр", []string{"Апокалипсис"}},
+	{Tobit, "Товит", "Тов", nil},
+	{Judith, "Иудифь", "Иудф", nil},
+	{Wisdom, "Премудрость Соломона", "Прем", nil},
+	{Sirach, "Сирах", "Сир", []string{"Премудрость Иисуса, сына Сирахова"}},
+	{Baruch, "Варух", "Вар", nil},
+	{LetterJeremiah, "Послание Иеремии", "Посл Иер", nil},
+	{Maccabees_1, "1 Маккавейская", "1 Мак", nil},
+	{Maccabees_2, "2 Маккавейская", "2 Мак", nil},
+	{Maccabees_3, "3 Маккавейская", "3 Мак", nil},
+	{Esdras_1, "2 Ездры", "2 Езд", nil},
+	{Esdras_2, "3 Ездры", "3 Езд", nil},
+	{Manasseh, "Молитва Манассии", "Мол Ман", nil},
+})
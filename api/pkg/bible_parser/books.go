@@ -0,0 +1,166 @@
+package verse
+
+// bookOSIS holds the OSIS identifier of every book, indexed by Book.
+var bookOSIS = []string{
+	"Gen", "Exod", "Lev", "Num", "Deut", "Josh", "Judg", "Ruth", "1Sam", "2Sam",
+	"1Kgs", "2Kgs", "1Chr", "2Chr", "Ezra", "Neh", "Esth", "Job", "Ps", "Prov",
+	"Eccl", "Song", "Isa", "Jer", "Lam", "Ezek", "Dan", "Hos", "Joel", "Amos",
+	"Obad", "Jonah", "Mic", "Nah", "Hab", "Zeph", "Hag", "Zech", "Mal",
+	"Matt", "Mark", "Luke", "John", "Acts", "Rom", "1Cor", "2Cor", "Gal", "Eph",
+	"Phil", "Col", "1Thess", "2Thess", "1Tim", "2Tim", "Titus", "Phlm", "Heb", "Jas",
+	"1Pet", "2Pet", "1John", "2John", "3John", "Jude", "Rev",
+	"Tob", "Jdt", "EsthGr", "Wis", "Sir", "Bar", "EpJer", "PrAzar", "Sus", "Bel",
+	"1Macc", "2Macc", "3Macc", "4Macc", "1Esd", "2Esd", "PrMan", "AddPs", "Odes", "PssSol",
+	"DanGr", "1En", "EpLao",
+}
+
+/*
+ * osisAliases are the other OSIS identifiers the Sword schemes use. The
+ * additions to Esther and Daniel are stored as the Greek books, which
+ * contain them.
+ */
+var osisAliases = map[string]Book{
+	"AddEsth": EstherGreek,
+	"AddDan":  DanielGreek,
+	"Ps151":   Psalm151,
+	"Laod":    Laodiceans,
+}
+
+/*
+ * bookAliases lists the common English abbreviations of each book in
+ * addition to its USFM code, OSIS identifier and full name, which are always
+ * accepted. Numbered books are listed with a leading digit; any ordinal the
+ * user types ("I", "1st", "First") is reduced to that digit before lookup.
+ */
+var bookAliases = map[Book][]string{
+	Genesis:         {"ge", "gn"},
+	Exodus:          {"ex", "exod"},
+	Leviticus:       {"le", "lv"},
+	Numbers:         {"nu", "nm", "nb"},
+	Deuteronomy:     {"de", "dt", "deut"},
+	Joshua:          {"jsh", "josh"},
+	Judges:          {"jg", "jdgs", "judg"},
+	Ruth:            {"ru", "rt", "rth"},
+	Samuel_1:        {"1s", "1sa", "1sam", "1sm"},
+	Samuel_2:        {"2s", "2sa", "2sam", "2sm"},
+	Kings_1:         {"1k", "1ki", "1kin", "1kng", "1kgs", "1kg"},
+	Kings_2:         {"2k", "2ki", "2kin", "2kng", "2kgs", "2kg"},
+	Chronicles_1:    {"1ch", "1chr", "1chron"},
+	Chronicles_2:    {"2ch", "2chr", "2chron"},
+	Ezra:            {"ezr"},
+	Nehemiah:        {"ne", "neh"},
+	Esther:          {"es", "esth"},
+	Job:             {"jb"},
+	Psalms:          {"ps", "psa", "psalm", "psm", "pslm"},
+	Proverbs:        {"pr", "prov", "prv"},
+	Ecclesiastes:    {"ec", "eccl", "eccles", "qoh"},
+	Song:            {"song", "sos", "songofsolomon", "canticles", "cant"},
+	Isaiah:          {"is", "isa"},
+	Jeremiah:        {"je", "jr"},
+	Lamentations:    {"la", "lam"},
+	Ezekiel:         {"eze", "ezek", "ezk"},
+	Daniel:          {"da", "dn"},
+	Hosea:           {"ho"},
+	Joel:            {"jl", "joe"},
+	Amos:            {"am"},
+	Obadiah:         {"ob", "obad"},
+	Jonah:           {"jnh", "jon"},
+	Micah:           {"mi", "mc"},
+	Nahum:           {"na", "nah"},
+	Habakkuk:        {"hb"},
+	Zephaniah:       {"zp", "zph", "zeph"},
+	Haggai:          {"hg"},
+	Zechariah:       {"zc", "zch", "zech"},
+	Malachi:         {"ml"},
+	Matthew:         {"mt", "matt"},
+	Mark:            {"mk", "mr", "mar"},
+	Luke:            {"lk", "lu"},
+	John:            {"jn", "joh"},
+	Acts:            {"ac"},
+	Romans:          {"ro", "rm"},
+	Corinthians_1:   {"1co", "1cor"},
+	Corinthians_2:   {"2co", "2cor"},
+	Galatians:       {"ga", "gl"},
+	Ephesians:       {"ephes"},
+	Philippians:     {"phil", "pp"},
+	Colossians:      {"co", "cl"},
+	Thessalonians_1: {"1th", "1thes", "1thess"},
+	Thessalonians_2: {"2th", "2thes", "2thess"},
+	Timothy_1:       {"1ti", "1tim", "1tm"},
+	Timothy_2:       {"2ti", "2tim", "2tm"},
+	Titus:           {"ti", "tt"},
+	Philemon:        {"philem", "pm", "phm"},
+	Hebrews:         {"he"},
+	James:           {"jas", "jm", "jms", "ja"},
+	Peter_1:         {"1p", "1pe", "1pet", "1pt", "1ptr"},
+	Peter_2:         {"2p", "2pe", "2pet", "2pt", "2ptr"},
+	John_1:          {"1j", "1jn", "1jo", "1jhn"},
+	John_2:          {"2j", "2jn", "2jo", "2jhn"},
+	John_3:          {"3j", "3jn", "3jo", "3jhn"},
+	Jude:            {"jud", "jd"},
+	Revelation:      {"re", "rv", "revelations", "apocalypse"},
+	Tobit:           {"tb", "tobias"},
+	Judith:          {"jth", "jdth"},
+	EstherGreek:     {"addesth", "add esth", "additions to esther", "rest of esther", "greek esther", "esg"},
+	Wisdom:          {"wis", "ws", "wisdom", "wisd", "wisd of sol"},
+	Sirach:          {"sir", "ecclesiasticus", "ecclus", "ben sira"},
+	Baruch:          {"bar", "ba"},
+	LetterJeremiah:  {"ep jer", "epistle of jeremiah", "epistle of jeremy", "let jer", "lje"},
+	SongThreeYouths: {"pr azar", "prayer of azariah", "song of the three children", "song of three youths", "sg three", "song of 3 young men"},
+	Susanna:         {"sus"},
+	Bel:             {"bel", "bel and dragon"},
+	Maccabees_1:     {"1macc", "1mac", "1ma", "1mc", "1m"},
+	Maccabees_2:     {"2macc", "2mac", "2ma", "2mc", "2m"},
+	Maccabees_3:     {"3macc", "3mac", "3ma", "3mc", "3m"},
+	Maccabees_4:     {"4macc", "4mac", "4ma", "4mc", "4m"},
+	Esdras_1:        {"1esd", "1es", "1esdr", "3ezra"},
+	Esdras_2:        {"2esd", "2es", "2esdr", "4ezra"},
+	Manasseh:        {"pr man", "prman", "prayer of manasses", "manasseh", "manasses"},
+	Psalm151:        {"ps151", "ps 151", "addps"},
+	Odes:            {"oda", "ode"},
+	PsalmsSolomon:   {"pss sol", "pssol", "ps sol"},
+	DanielGreek:     {"addan", "add dan", "additions to daniel", "greek daniel"},
+	Enoch:           {"1en", "1enoch", "enoch", "eno"},
+	Laodiceans:      {"laod", "laodiceans", "eplao"},
+}
+
+// Books with a single chapter, where "Jude 5" means verse 5.
+var singleChapterBooks = map[Book]bool{
+	Obadiah:  true,
+	Philemon: true,
+	John_2:   true,
+	John_3:   true,
+	Jude:     true,
+
+	LetterJeremiah:  true,
+	SongThreeYouths: true,
+	Susanna:         true,
+	Bel:             true,
+	Manasseh:        true,
+	Psalm151:        true,
+	Laodiceans:      true,
+}
+
+// OSIS returns the OSIS identifier of the book (e.g. "Gen").
+func (b Book) OSIS() string {
+	if !b.Valid() {
+		return ""
+	}
+	return bookOSIS[b]
+}
+
+// SingleChapter reports whether the book has only one chapter.
+func (b Book) SingleChapter() bool {
+	return singleChapterBooks[b]
+}
+
+// BookFromOSIS returns the book identified by the given OSIS identifier.
+func BookFromOSIS(osis string) (Book, bool) {
+	for i, id := range bookOSIS {
+		if id == osis {
+			return Book(i), true
+		}
+	}
+	book, ok := osisAliases[osis]
+	return book, ok
+}
@@ -0,0 +1,124 @@
+package verse
+
+import (
+	"strings"
+)
+
+// Canon is a set of canons, one bit per tradition.
+type Canon uint8
+
+const (
+	// The 66 books of the Protestant Old and New Testaments.
+	CANON_PROTESTANT Canon = 1 << iota
+	// The Roman Catholic canon: Protestant plus the deuterocanon, with the
+	// Greek Esther and Daniel (canon_catholic.h).
+	CANON_CATHOLIC
+	// The Eastern Orthodox canon, including the Greek and Slavonic
+	// appendices (canon_orthodox.h, canon_synodal.h).
+	CANON_ORTHODOX
+	// The King James Version with Apocrypha (canon_kjva.h).
+	CANON_KJVA
+	// The Ethiopian Orthodox canon.
+	CANON_ETHIOPIAN
+
+	CANON_NONE Canon = 0
+	CANON_ALL        = CANON_PROTESTANT | CANON_CATHOLIC | CANON_ORTHODOX | CANON_KJVA | CANON_ETHIOPIAN
+)
+
+var canonNames = []struct {
+	canon Canon
+	name  string
+}{
+	{CANON_PROTESTANT, "protestant"},
+	{CANON_CATHOLIC, "catholic"},
+	{CANON_ORTHODOX, "orthodox"},
+	{CANON_KJVA, "kjva"},
+	{CANON_ETHIOPIAN, "ethiopian"},
+}
+
+// String lists the canons in the set, e.g. "catholic|orthodox".
+func (c Canon) String() string {
+	var names []string
+	for _, n := range canonNames {
+		if c&n.canon != 0 {
+			names = append(names, n.name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "|")
+}
+
+// CanonFromName returns the canon with the given String() name.
+func CanonFromName(name string) (Canon, bool) {
+	for _, n := range canonNames {
+		if strings.EqualFold(name, n.name) {
+			return n.canon, true
+		}
+	}
+	return CANON_NONE, false
+}
+
+/*
+ * deuterocanonCanons records which canons include each book outside the
+ * Protestant 66. The Protestant books belong to every canon. KJVA prints
+ * the Letter of Jeremiah as Baruch 6, as do the Catholic bibles.
+ */
+var deuterocanonCanons = map[Book]Canon{
+	Tobit:           CANON_CATHOLIC | CANON_ORTHODOX | CANON_KJVA | CANON_ETHIOPIAN,
+	Judith:          CANON_CATHOLIC | CANON_ORTHODOX | CANON_KJVA | CANON_ETHIOPIAN,
+	EstherGreek:     CANON_CATHOLIC | CANON_ORTHODOX | CANON_KJVA | CANON_ETHIOPIAN,
+	Wisdom:          CANON_CATHOLIC | CANON_ORTHODOX | CANON_KJVA | CANON_ETHIOPIAN,
+	Sirach:          CANON_CATHOLIC | CANON_ORTHODOX | CANON_KJVA | CANON_ETHIOPIAN,
+	Baruch:          CANON_CATHOLIC | CANON_ORTHODOX | CANON_KJVA | CANON_ETHIOPIAN,
+	LetterJeremiah:  CANON_CATHOLIC | CANON_ORTHODOX | CANON_KJVA | CANON_ETHIOPIAN,
+	SongThreeYouths: CANON_CATHOLIC | CANON_ORTHODOX | CANON_KJVA | CANON_ETHIOPIAN,
+	Susanna:         CANON_CATHOLIC | CANON_ORTHODOX | CANON_KJVA | CANON_ETHIOPIAN,
+	Bel:             CANON_CATHOLIC | CANON_ORTHODOX | CANON_KJVA | CANON_ETHIOPIAN,
+	Maccabees_1:     CANON_CATHOLIC | CANON_ORTHODOX | CANON_KJVA,
+	Maccabees_2:     CANON_CATHOLIC | CANON_ORTHODOX | CANON_KJVA,
+	Maccabees_3:     CANON_ORTHODOX,
+	Maccabees_4:     CANON_ORTHODOX,
+	Esdras_1:        CANON_ORTHODOX | CANON_KJVA | CANON_ETHIOPIAN,
+	Esdras_2:        CANON_ORTHODOX | CANON_KJVA | CANON_ETHIOPIAN,
+	Manasseh:        CANON_ORTHODOX | CANON_KJVA | CANON_ETHIOPIAN,
+	Psalm151:        CANON_ORTHODOX | CANON_ETHIOPIAN,
+	Odes:            CANON_NONE,
+	PsalmsSolomon:   CANON_NONE,
+	DanielGreek:     CANON_CATHOLIC | CANON_ORTHODOX | CANON_ETHIOPIAN,
+	Enoch:           CANON_ETHIOPIAN,
+	Laodiceans:      CANON_NONE,
+}
+
+// Canons returns the canons that include the book.
+func (b Book) Canons() Canon {
+	if !b.Valid() {
+		return CANON_NONE
+	}
+	if canons, ok := deuterocanonCanons[b]; ok {
+		return canons
+	}
+	return CANON_ALL
+}
+
+// Deuterocanonical reports whether the book is outside the Protestant canon.
+func (b Book) Deuterocanonical() bool {
+	return b.Valid() && b.Canons()&CANON_PROTESTANT == 0
+}
+
+// Includes reports whether any canon in c includes the book.
+func (c Canon) Includes(book Book) bool {
+	return book.Canons()&c != 0
+}
+
+// Books returns, in Book order, every book included by any canon in c.
+func (c Canon) Books() []Book {
+	var books []Book
+	for b := Book(0); b.Valid(); b++ {
+		if c.Includes(b) {
+			books = append(books, b)
+		}
+	}
+	return books
+}
@@ -0,0 +1,240 @@
+package verse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Style selects how references are written out.
+type Style byte
+
+const (
+	// "Gen.1.1-Gen.1.3"
+	STYLE_OSIS Style = iota
+	// "GEN 1:1-3"
+	STYLE_USFM
+	// "GEN.1.1-3", the reference path of the web-api v2 verses route
+	STYLE_API
+	// "Gen 1:1-3"
+	STYLE_SBL
+	// "Genesis 1:1-3"
+	STYLE_FULL
+)
+
+func (s Style) String() string {
+	switch s {
+	case STYLE_OSIS:
+		return "osis"
+	case STYLE_USFM:
+		return "usfm"
+	case STYLE_API:
+		return "api"
+	case STYLE_SBL:
+		return "sbl"
+	case STYLE_FULL:
+		return "full"
+	}
+	return "unknown"
+}
+
+// StyleFromName returns the Style with the given String() name.
+func StyleFromName(name string) (Style, bool) {
+	for s := STYLE_OSIS; s <= STYLE_FULL; s++ {
+		if strings.EqualFold(name, s.String()) {
+			return s, true
+		}
+	}
+	return 0, false
+}
+
+/**
+ * Formatter writes references in one citation style.
+ *
+ * Names selects the language of the SBL and full styles and defaults to
+ * English. The web-api path style cannot express whole books or ranges of
+ * whole chapters, so when Versification is set those are first resolved to
+ * verse ranges ("GEN.1-3" would read as Genesis 1:1-3). Without it they are
+ * written with the book repeated ("GEN.1-GEN.3"), which the route rejects
+ * rather than misreads.
+ */
+type Formatter struct {
+	Style         Style
+	Names         *BookNames
+	Versification *Versification
+}
+
+// FormatReference writes ref in style using English book names.
+func FormatReference(ref BibleReference, style Style) string {
+	return Formatter{Style: style}.Reference(ref)
+}
+
+// FormatReferenceList writes refs in style using English book names.
+func FormatReferenceList(refs []BibleReference, style Style) string {
+	return Formatter{Style: style}.List(refs)
+}
+
+func (f Formatter) names() *BookNames {
+	if f.Names == nil {
+		return EnglishBookNames
+	}
+	return f.Names
+}
+
+func (f Formatter) book(book Book) string {
+	switch f.Style {
+	case STYLE_OSIS:
+		return book.OSIS()
+	case STYLE_USFM, STYLE_API:
+		return book.Code()
+	case STYLE_SBL:
+		return f.names().Abbreviation(book)
+	}
+	return f.names().Name(book)
+}
+
+// Reference writes a single reference.
+func (f Formatter) Reference(ref BibleReference) string {
+	switch f.Style {
+	case STYLE_OSIS:
+		return f.osis(ref)
+	case STYLE_API:
+		return f.api(ref)
+	}
+
+	span := f.span(ref)
+	if span == "" {
+		return f.book(ref.Book)
+	}
+	return f.book(ref.Book) + " " + span
+}
+
+/**
+ * List writes a list of references. The citation styles collapse repeated
+ * books and chapters the way ParseReferenceList reads them back:
+ * "Gen 1:1-3, 5; 2:4; Exod 3". OSIS references are separated by spaces, as
+ * in an osisRef attribute, and web-api paths by commas.
+ */
+func (f Formatter) List(refs []BibleReference) string {
+	var sb strings.Builder
+	for i, ref := range refs {
+		if i == 0 {
+			sb.WriteString(f.Reference(ref))
+			continue
+		}
+
+		prev := refs[i-1]
+		switch {
+		case f.Style == STYLE_OSIS:
+			sb.WriteString(" " + f.Reference(ref))
+		case f.Style == STYLE_API:
+			sb.WriteString("," + f.Reference(ref))
+		case ref.Book != prev.Book || ref.Granularity == GRANULARITY_BOOK || prev.Granularity == GRANULARITY_BOOK:
+			sb.WriteString("; " + f.Reference(ref))
+		case prev.Granularity == GRANULARITY_VERSE && ref.Granularity == GRANULARITY_VERSE && ref.StartChapter == prev.EndChapter:
+			// More verses of the chapter the previous item ended in
+			sb.WriteString(", " + verseSpan(ref))
+		default:
+			if span := f.span(ref); span != "" {
+				sb.WriteString("; " + span)
+			} else {
+				sb.WriteString("; " + f.Reference(ref))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// span writes the chapter/verse portion of ref in the citation styles.
+func (f Formatter) span(ref BibleReference) string {
+	// SBL and full names cite single chapter books by verse alone: "Jude 5"
+	omitChapter := (f.Style == STYLE_SBL || f.Style == STYLE_FULL) &&
+		ref.Book.SingleChapter() && ref.StartChapter == 1 && ref.EndChapter == 1
+
+	switch ref.Granularity {
+	case GRANULARITY_BOOK:
+		return ""
+	case GRANULARITY_CHAPTER:
+		if omitChapter {
+			return ""
+		}
+		if ref.StartChapter == ref.EndChapter {
+			return fmt.Sprint(ref.StartChapter)
+		}
+		return fmt.Sprintf("%d-%d", ref.StartChapter, ref.EndChapter)
+	}
+
+	if omitChapter {
+		return verseSpan(ref)
+	}
+	return fmt.Sprintf("%d:%s", ref.StartChapter, verseSpan(ref))
+}
+
+// verseSpan writes a verse level reference from its first verse on: "1-3"
+// or "1-2:3".
+func verseSpan(ref BibleReference) string {
+	switch {
+	case ref.StartChapter == ref.EndChapter && ref.StartVerse == ref.EndVerse:
+		return fmt.Sprint(ref.StartVerse)
+	case ref.StartChapter == ref.EndChapter:
+		return fmt.Sprintf("%d-%d", ref.StartVerse, ref.EndVerse)
+	}
+	return fmt.Sprintf("%d-%d:%d", ref.StartVerse, ref.EndChapter, ref.EndVerse)
+}
+
+func (f Formatter) osis(ref BibleReference) string {
+	book := f.book(ref.Book)
+
+	var from, to string
+	switch ref.Granularity {
+	case GRANULARITY_BOOK:
+		return book
+	case GRANULARITY_CHAPTER:
+		from = fmt.Sprintf("%s.%d", book, ref.StartChapter)
+		to = fmt.Sprintf("%s.%d", book, ref.EndChapter)
+	default:
+		from = fmt.Sprintf("%s.%d.%d", book, ref.StartChapter, ref.StartVerse)
+		to = fmt.Sprintf("%s.%d.%d", book, ref.EndChapter, ref.EndVerse)
+	}
+
+	if from == to {
+		return from
+	}
+	return from + "-" + to
+}
+
+func (f Formatter) api(ref BibleReference) string {
+	book := f.book(ref.Book)
+
+	wholeChapters := ref.Granularity == GRANULARITY_BOOK ||
+		(ref.Granularity == GRANULARITY_CHAPTER && ref.StartChapter != ref.EndChapter)
+	if wholeChapters && f.Versification != nil {
+		if resolved, err := f.Versification.Resolve(ref); err == nil {
+			ref = resolved
+			ref.Granularity = GRANULARITY_VERSE
+		}
+	}
+
+	switch ref.Granularity {
+	case GRANULARITY_BOOK:
+		return book
+	case GRANULARITY_CHAPTER:
+		if ref.StartChapter == ref.EndChapter {
+			return fmt.Sprintf("%s.%d", book, ref.StartChapter)
+		}
+		return fmt.Sprintf("%s.%d-%s.%d", book, ref.StartChapter, book, ref.EndChapter)
+	}
+
+	s := fmt.Sprintf("%s.%d.%d", book, ref.StartChapter, ref.StartVerse)
+	switch {
+	case ref.StartChapter != ref.EndChapter:
+		s += fmt.Sprintf("-%d.%d", ref.EndChapter, ref.EndVerse)
+	case ref.StartVerse != ref.EndVerse:
+		s += fmt.Sprintf("-%d", ref.EndVerse)
+	}
+	return s
+}
+
+// Format writes ref in style using English book names.
+func (ref BibleReference) Format(style Style) string {
+	return FormatReference(ref, style)
+}
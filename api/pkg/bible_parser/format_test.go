@@ -0,0 +1,67 @@
+package verse
+
+import "testing"
+
+func TestFormatReferenceRoundTrip(t *testing.T) {
+	for book := Book(0); book.Valid(); book++ {
+		refs := []BibleReference{
+			{Book: book, Granularity: GRANULARITY_BOOK},
+			{Book: book, StartChapter: 1, StartVerse: 1, EndChapter: 1, EndVerse: 2, Granularity: GRANULARITY_VERSE},
+		}
+		// "Jude 1" is a verse of a single chapter book
+		if !book.SingleChapter() {
+			refs = append(refs, BibleReference{Book: book, StartChapter: 1, EndChapter: 2, Granularity: GRANULARITY_CHAPTER})
+		}
+		for _, ref := range refs {
+			for style := STYLE_OSIS; style <= STYLE_FULL; style++ {
+				text := FormatReference(ref, style)
+				got, err := NormalizeBibleReference(text)
+				if err != nil {
+					t.Errorf("%v (%s) %q: %v", book, style, text, err)
+				} else if got != ref {
+					t.Errorf("%v (%s) %q = %+v, want %+v", book, style, text, got, ref)
+				}
+			}
+		}
+	}
+}
+
+func TestLookupBookCodes(t *testing.T) {
+	for book := Book(0); book.Valid(); book++ {
+		for _, id := range []string{book.Code(), book.OSIS()} {
+			if got, ok := LookupBook(id); !ok || got != book {
+				t.Errorf("LookupBook(%q) = %v, %v, want %v", id, got, ok, book)
+			}
+		}
+	}
+}
+
+func TestNormalizeBibleReferenceBookNames(t *testing.T) {
+	tests := []struct {
+		input string
+		want  BibleReference
+	}{
+		{"PSS 1:1", verseRef(PsalmsSolomon, 1, 1, 1, 1)},
+		{"PS2 1:1", verseRef(Psalm151, 1, 1, 1, 1)},
+		{"PS2.1.1", verseRef(Psalm151, 1, 1, 1, 1)},
+		{"Ps 151 1", verseRef(Psalm151, 1, 1, 1, 1)},
+		{"Psalm 151", BibleReference{Book: Psalm151, Granularity: GRANULARITY_BOOK}},
+		{"S3Y 1:1", verseRef(SongThreeYouths, 1, 1, 1, 1)},
+		{"Ps 1:1", verseRef(Psalms, 1, 1, 1, 1)},
+		{"Ps 15", chapterRef(Psalms, 15, 15)},
+		{"Ps 2", chapterRef(Psalms, 2, 2)},
+		{"Ps.2.1", verseRef(Psalms, 2, 1, 2, 1)},
+		{"Ps.1-Ps.2", chapterRef(Psalms, 1, 2)},
+		{"Ps 150:1", verseRef(Psalms, 150, 1, 150, 1)},
+		{"1 Sam 2:1", verseRef(Samuel_1, 2, 1, 2, 1)},
+		{"2Kgs 1", chapterRef(Kings_2, 1, 1)},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeBibleReference(tt.input)
+		if err != nil {
+			t.Errorf("%q: %v", tt.input, err)
+		} else if got != tt.want {
+			t.Errorf("%q = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
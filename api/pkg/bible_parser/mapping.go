@@ -0,0 +1,326 @@
+package verse
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+/*
+ * verseMapping is one entry of a scheme's mapping onto the KJVA scheme, see
+ * versification/README.md. A non-zero verseEnd identifies a range of verses
+ * that map together (many-to-one); a non-zero kjvaVerseEnd a verse that is
+ * split across several KJVA verses.
+ */
+type verseMapping struct {
+	book         Book
+	chapter      uint
+	verse        uint
+	verseEnd     uint
+	kjvaBook     Book
+	kjvaChapter  uint
+	kjvaVerse    uint
+	kjvaVerseEnd uint
+}
+
+/**
+ * LoadVersificationMappings reads the mapping JSON file at path (e.g.
+ * versification/sword-1.9.0-mappings.json) and attaches each scheme's
+ * mappings to the matching entry of schemes.
+ */
+func LoadVersificationMappings(schemes map[string]*Versification, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return ReadVersificationMappings(schemes, file)
+}
+
+// ReadVersificationMappings reads mapping JSON into schemes.
+func ReadVersificationMappings(schemes map[string]*Versification, r io.Reader) error {
+	var raw map[string][][8]interface{}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return err
+	}
+
+	for name, entries := range raw {
+		v11n, ok := schemes[name]
+		if !ok {
+			return fmt.Errorf("mappings for unknown versification %q", name)
+		}
+
+		v11n.mappings = v11n.mappings[:0]
+		for _, entry := range entries {
+			m, ok, err := decodeMapping(entry)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			if !ok {
+				// A book we do not model
+				continue
+			}
+			v11n.mappings = append(v11n.mappings, m)
+		}
+	}
+	return nil
+}
+
+func decodeMapping(entry [8]interface{}) (m verseMapping, ok bool, err error) {
+	var nums [6]uint
+	var names [2]string
+	for i, field := range entry {
+		switch val := field.(type) {
+		case string:
+			if i != 0 && i != 4 {
+				return m, false, fmt.Errorf("unexpected book %q in mapping %v", val, entry)
+			}
+			names[i/4] = val
+		case float64:
+			if i == 0 || i == 4 || val < 0 {
+				return m, false, fmt.Errorf("unexpected number %v in mapping %v", val, entry)
+			}
+			idx := i - 1
+			if i > 4 {
+				idx = i - 2
+			}
+			nums[idx] = uint(val)
+		default:
+			return m, false, fmt.Errorf("invalid mapping %v", entry)
+		}
+	}
+
+	book, bookOk := BookFromOSIS(names[0])
+	kjvaBook, kjvaOk := BookFromOSIS(names[1])
+	if !bookOk || !kjvaOk {
+		return m, false, nil
+	}
+
+	m = verseMapping{
+		book:         book,
+		chapter:      nums[0],
+		verse:        nums[1],
+		verseEnd:     nums[2],
+		kjvaBook:     kjvaBook,
+		kjvaChapter:  nums[3],
+		kjvaVerse:    nums[4],
+		kjvaVerseEnd: nums[5],
+	}
+	return m, true, nil
+}
+
+func maxUint(a, b uint) uint {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+/*
+ * toKJVA maps a verse of the scheme onto the KJVA scheme, returning the KJVA
+ * verse range (verseEnd is 0 for a single verse).
+ *
+ * Follows the forward mapping of VersificationMgr::System::translateVerse()
+ * in sword/src/mgr/versificationmgr.cpp.
+ */
+func (v *Versification) toKJVA(book Book, chapter uint, verse uint) (Book, uint, uint, uint) {
+	// Search backwards for the closest entry at or before the verse
+	for i := len(v.mappings) - 1; i >= 0; i-- {
+		m := v.mappings[i]
+		if m.book != book {
+			continue
+		}
+		if m.chapter < chapter {
+			break
+		}
+		if m.chapter != chapter || m.verse > verse {
+			continue
+		}
+
+		if m.verse == verse || m.verseEnd >= verse {
+			return m.kjvaBook, m.kjvaChapter, m.kjvaVerse, m.kjvaVerseEnd
+		}
+		// Shift the same as the end of the entry
+		shifted := int(verse) + int(maxUint(m.kjvaVerse, m.kjvaVerseEnd)) - int(maxUint(m.verse, m.verseEnd))
+		if shifted < 0 {
+			shifted = 0
+		}
+		return m.kjvaBook, m.kjvaChapter, uint(shifted), 0
+	}
+	return book, chapter, verse, 0
+}
+
+/*
+ * fromKJVA maps a KJVA verse onto the scheme, returning the verse range
+ * within the scheme (verseEnd is 0 for a single verse).
+ *
+ * Follows the reverse mapping of VersificationMgr::System::translateVerse()
+ * in sword/src/mgr/versificationmgr.cpp.
+ */
+func (v *Versification) fromKJVA(book Book, chapter uint, verse uint) (Book, uint, uint, uint) {
+	var closest *verseMapping
+	for i := range v.mappings {
+		m := &v.mappings[i]
+		if m.kjvaBook != book || m.kjvaChapter != chapter || m.kjvaVerse > verse {
+			continue
+		}
+
+		if m.kjvaVerse == verse || m.kjvaVerseEnd >= verse {
+			return m.book, m.chapter, m.verse, m.verseEnd
+		}
+		// Duplicate entries are possible, use the last one
+		if closest == nil || maxUint(closest.kjvaVerse, closest.kjvaVerseEnd) <= maxUint(m.kjvaVerse, m.kjvaVerseEnd) {
+			closest = m
+		}
+	}
+
+	if closest != nil {
+		shifted := int(verse) + int(maxUint(closest.verse, closest.verseEnd)) - int(maxUint(closest.kjvaVerse, closest.kjvaVerseEnd))
+		if shifted < 0 {
+			shifted = 0
+		}
+		return closest.book, closest.chapter, uint(shifted), 0
+	}
+	return book, chapter, verse, 0
+}
+
+/**
+ * MapVerse converts a single verse of this scheme into the scheme dst. The
+ * result is a range when the verse is split across several verses of dst,
+ * and several verses of this scheme may map to the same verse of dst.
+ *
+ * Mapping goes through the KJVA scheme, as Sword does. A verse number of 0
+ * denotes a psalm title, which some schemes number as verse 1; a title that
+ * dst does not number maps to the whole psalm.
+ */
+func (v *Versification) MapVerse(dst *Versification, book Book, chapter uint, verse uint) (BibleReference, error) {
+	ref, err := v.mapVerse(dst, book, chapter, verse)
+	if err != nil {
+		return ref, err
+	}
+	return dst.settleTitle(ref), nil
+}
+
+// mapVerse is MapVerse keeping verse 0 for the psalm titles of dst.
+func (v *Versification) mapVerse(dst *Versification, book Book, chapter uint, verse uint) (BibleReference, error) {
+	kBook, kChapter, kVerse, kVerseEnd := v.toKJVA(book, chapter, verse)
+	if kVerseEnd < kVerse {
+		kVerseEnd = kVerse
+	}
+
+	startBook, startChapter, startVerse, _ := dst.fromKJVA(kBook, kChapter, kVerse)
+	endBook, endChapter, endVerse, endVerseEnd := dst.fromKJVA(kBook, kChapter, kVerseEnd)
+	endVerse = maxUint(endVerse, endVerseEnd)
+
+	ref := BibleReference{
+		Book:         startBook,
+		StartChapter: startChapter,
+		StartVerse:   startVerse,
+		EndChapter:   endChapter,
+		EndVerse:     endVerse,
+		Granularity:  GRANULARITY_VERSE,
+	}
+	if startBook != endBook {
+		return ref, &VersificationError{Scheme: dst.Name, Ref: ref, Msg: fmt.Sprintf("%s %d:%d maps across books", book, chapter, verse)}
+	}
+	if err := dst.validateMapped(ref); err != nil {
+		return ref, err
+	}
+	return ref, nil
+}
+
+/**
+ * settleTitle turns the psalm titles (verse 0) left in a mapped reference
+ * into verses that exist: a range starting on a title starts on verse 1, and
+ * a title alone stands for its chapter.
+ */
+func (v *Versification) settleTitle(ref BibleReference) BibleReference {
+	switch {
+	case ref.Granularity != GRANULARITY_VERSE:
+	case ref.StartVerse == 0 && ref.EndVerse == 0:
+		ref.Granularity = GRANULARITY_CHAPTER
+	case ref.StartVerse == 0:
+		ref.StartVerse = 1
+	case ref.EndVerse == 0 && ref.EndChapter > ref.StartChapter:
+		// Ending on the title of the next chapter
+		ref.EndChapter--
+		ref.EndVerse = v.VerseCount(ref.Book, ref.EndChapter)
+	}
+	return ref
+}
+
+// validateMapped is Validate for mapped references, which may include
+// verse 0 (a psalm title).
+func (v *Versification) validateMapped(ref BibleReference) error {
+	check := ref
+	if check.StartVerse == 0 {
+		check.StartVerse = 1
+	}
+	if check.EndVerse == 0 {
+		check.EndVerse = 1
+	}
+	return v.Validate(check)
+}
+
+/**
+ * MapReference converts ref from this scheme into the scheme dst, e.g.
+ * Synodal Psalm 22 is KJV Psalm 23 and KJV Romans 16:25 is Synodal
+ * Romans 14:24.
+ */
+func (v *Versification) MapReference(dst *Versification, ref BibleReference) (BibleReference, error) {
+	resolved, err := v.Resolve(ref)
+	if err != nil {
+		return ref, err
+	}
+	if v == dst || v.Name == dst.Name {
+		return ref, nil
+	}
+
+	if ref.Granularity == GRANULARITY_BOOK {
+		if !dst.HasBook(ref.Book) {
+			return ref, &VersificationError{Scheme: dst.Name, Ref: ref, Msg: fmt.Sprintf("%s is not part of this versification", ref.Book)}
+		}
+		return ref, nil
+	}
+
+	start, err := v.mapVerse(dst, resolved.Book, resolved.StartChapter, resolved.StartVerse)
+	if err != nil {
+		return ref, err
+	}
+
+	// Start whole chapters from any psalm title
+	if ref.Granularity == GRANULARITY_CHAPTER && resolved.Book == Psalms {
+		title, err := v.mapVerse(dst, resolved.Book, resolved.StartChapter, 0)
+		if err == nil && title.Book == start.Book && title.StartChapter == start.StartChapter &&
+			title.StartVerse < start.StartVerse {
+			start = title
+		}
+	}
+	end, err := v.mapVerse(dst, resolved.Book, resolved.EndChapter, resolved.EndVerse)
+	if err != nil {
+		return ref, err
+	}
+	if start.Book != end.Book {
+		return ref, &VersificationError{Scheme: dst.Name, Ref: ref, Msg: "reference maps across books"}
+	}
+
+	mapped := BibleReference{
+		Book:         start.Book,
+		StartChapter: start.StartChapter,
+		StartVerse:   start.StartVerse,
+		EndChapter:   end.EndChapter,
+		EndVerse:     end.EndVerse,
+		Granularity:  GRANULARITY_VERSE,
+	}
+
+	// Whole chapters that still line up stay whole chapters
+	if ref.Granularity == GRANULARITY_CHAPTER && mapped.StartVerse <= 1 &&
+		mapped.EndVerse == dst.VerseCount(mapped.Book, mapped.EndChapter) {
+		mapped.Granularity = GRANULARITY_CHAPTER
+		mapped.StartVerse = 0
+		mapped.EndVerse = 0
+	}
+	return dst.settleTitle(mapped), nil
+}
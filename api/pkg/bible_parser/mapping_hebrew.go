@@ -0,0 +1,161 @@
+package verse
+
+// Schemes numbering chapters and verses as the Hebrew Bible does.
+var hebrewSchemes = map[string]bool{"MT": true, "Leningrad": true}
+
+/**
+ * hebrewMappings maps the Hebrew numbering of the MT and Leningrad schemes
+ * onto the KJVA scheme, in the same form as the Sword mappings: psalm titles
+ * counted as verses, and the chapters that start a few verses earlier or
+ * later (Mal 3:19-24 is KJVA Mal 4:1-6). Sword has no mappings for these
+ * schemes although they do not number verses as KJVA does.
+ *
+ * A KJVA verse split between two Hebrew chapters maps back to where it
+ * starts, which is what the entries mapping a verse onto itself are for.
+ */
+var hebrewMappings = []verseMapping{
+	{Genesis, 32, 1, 0, Genesis, 31, 55, 0},
+	{Genesis, 32, 2, 0, Genesis, 32, 1, 0},
+	{Exodus, 7, 26, 0, Exodus, 8, 1, 0},
+	{Exodus, 8, 1, 0, Exodus, 8, 5, 0},
+	{Exodus, 21, 37, 0, Exodus, 22, 1, 0},
+	{Exodus, 22, 1, 0, Exodus, 22, 2, 0},
+	{Leviticus, 5, 20, 0, Leviticus, 6, 1, 0},
+	{Leviticus, 6, 1, 0, Leviticus, 6, 8, 0},
+	{Numbers, 17, 1, 0, Numbers, 16, 36, 0},
+	{Numbers, 17, 16, 0, Numbers, 17, 1, 0},
+	{Numbers, 25, 19, 0, Numbers, 26, 1, 0},
+	{Numbers, 26, 1, 0, Numbers, 26, 1, 0},
+	{Numbers, 30, 1, 0, Numbers, 29, 40, 0},
+	{Numbers, 30, 2, 0, Numbers, 30, 1, 0},
+	{Deuteronomy, 13, 1, 0, Deuteronomy, 12, 32, 0},
+	{Deuteronomy, 13, 2, 0, Deuteronomy, 13, 1, 0},
+	{Deuteronomy, 23, 1, 0, Deuteronomy, 22, 30, 0},
+	{Deuteronomy, 23, 2, 0, Deuteronomy, 23, 1, 0},
+	{Deuteronomy, 28, 69, 0, Deuteronomy, 29, 1, 0},
+	{Deuteronomy, 29, 1, 0, Deuteronomy, 29, 2, 0},
+	{Samuel_1, 20, 42, 0, Samuel_1, 20, 42, 0},
+	{Samuel_1, 21, 1, 0, Samuel_1, 20, 42, 0},
+	{Samuel_1, 21, 2, 0, Samuel_1, 21, 1, 0},
+	{Samuel_1, 24, 1, 0, Samuel_1, 23, 29, 0},
+	{Samuel_1, 24, 2, 0, Samuel_1, 24, 1, 0},
+	{Samuel_2, 19, 1, 0, Samuel_2, 18, 33, 0},
+	{Samuel_2, 19, 2, 0, Samuel_2, 19, 1, 0},
+	{Kings_1, 5, 1, 0, Kings_1, 4, 21, 0},
+	{Kings_1, 5, 15, 0, Kings_1, 5, 1, 0},
+	{Kings_1, 22, 43, 44, Kings_1, 22, 43, 0},
+	{Kings_1, 22, 45, 0, Kings_1, 22, 44, 0},
+	{Kings_2, 12, 1, 0, Kings_2, 11, 21, 0},
+	{Kings_2, 12, 2, 0, Kings_2, 12, 1, 0},
+	{Isaiah, 8, 23, 0, Isaiah, 9, 1, 0},
+	{Isaiah, 9, 1, 0, Isaiah, 9, 2, 0},
+	{Isaiah, 63, 19, 0, Isaiah, 64, 1, 0},
+	{Isaiah, 63, 19, 0, Isaiah, 63, 19, 0},
+	{Isaiah, 64, 1, 0, Isaiah, 64, 2, 0},
+	{Jeremiah, 8, 23, 0, Jeremiah, 9, 1, 0},
+	{Jeremiah, 9, 1, 0, Jeremiah, 9, 2, 0},
+	{Ezekiel, 21, 1, 0, Ezekiel, 20, 45, 0},
+	{Ezekiel, 21, 6, 0, Ezekiel, 21, 1, 0},
+	{Hosea, 2, 1, 0, Hosea, 1, 10, 0},
+	{Hosea, 2, 3, 0, Hosea, 2, 1, 0},
+	{Hosea, 12, 1, 0, Hosea, 11, 12, 0},
+	{Hosea, 12, 2, 0, Hosea, 12, 1, 0},
+	{Hosea, 14, 1, 0, Hosea, 13, 16, 0},
+	{Hosea, 14, 2, 0, Hosea, 14, 1, 0},
+	{Joel, 3, 1, 0, Joel, 2, 28, 0},
+	{Joel, 4, 1, 0, Joel, 3, 1, 0},
+	{Jonah, 2, 1, 0, Jonah, 1, 17, 0},
+	{Jonah, 2, 2, 0, Jonah, 2, 1, 0},
+	{Micah, 4, 14, 0, Micah, 5, 1, 0},
+	{Micah, 5, 1, 0, Micah, 5, 2, 0},
+	{Nahum, 2, 1, 0, Nahum, 1, 15, 0},
+	{Nahum, 2, 2, 0, Nahum, 2, 1, 0},
+	{Zechariah, 2, 1, 0, Zechariah, 1, 18, 0},
+	{Zechariah, 2, 5, 0, Zechariah, 2, 1, 0},
+	{Malachi, 3, 19, 0, Malachi, 4, 1, 0},
+	{Psalms, 3, 1, 0, Psalms, 3, 0, 0},
+	{Psalms, 4, 1, 0, Psalms, 4, 0, 0},
+	{Psalms, 5, 1, 0, Psalms, 5, 0, 0},
+	{Psalms, 6, 1, 0, Psalms, 6, 0, 0},
+	{Psalms, 7, 1, 0, Psalms, 7, 0, 0},
+	{Psalms, 8, 1, 0, Psalms, 8, 0, 0},
+	{Psalms, 9, 1, 0, Psalms, 9, 0, 0},
+	{Psalms, 12, 1, 0, Psalms, 12, 0, 0},
+	{Psalms, 18, 1, 0, Psalms, 18, 0, 0},
+	{Psalms, 19, 1, 0, Psalms, 19, 0, 0},
+	{Psalms, 20, 1, 0, Psalms, 20, 0, 0},
+	{Psalms, 21, 1, 0, Psalms, 21, 0, 0},
+	{Psalms, 22, 1, 0, Psalms, 22, 0, 0},
+	{Psalms, 30, 1, 0, Psalms, 30, 0, 0},
+	{Psalms, 31, 1, 0, Psalms, 31, 0, 0},
+	{Psalms, 34, 1, 0, Psalms, 34, 0, 0},
+	{Psalms, 36, 1, 0, Psalms, 36, 0, 0},
+	{Psalms, 38, 1, 0, Psalms, 38, 0, 0},
+	{Psalms, 39, 1, 0, Psalms, 39, 0, 0},
+	{Psalms, 40, 1, 0, Psalms, 40, 0, 0},
+	{Psalms, 41, 1, 0, Psalms, 41, 0, 0},
+	{Psalms, 42, 1, 0, Psalms, 42, 0, 0},
+	{Psalms, 44, 1, 0, Psalms, 44, 0, 0},
+	{Psalms, 45, 1, 0, Psalms, 45, 0, 0},
+	{Psalms, 46, 1, 0, Psalms, 46, 0, 0},
+	{Psalms, 47, 1, 0, Psalms, 47, 0, 0},
+	{Psalms, 48, 1, 0, Psalms, 48, 0, 0},
+	{Psalms, 49, 1, 0, Psalms, 49, 0, 0},
+	{Psalms, 51, 1, 2, Psalms, 51, 0, 0},
+	{Psalms, 52, 1, 2, Psalms, 52, 0, 0},
+	{Psalms, 53, 1, 0, Psalms, 53, 0, 0},
+	{Psalms, 54, 1, 2, Psalms, 54, 0, 0},
+	{Psalms, 55, 1, 0, Psalms, 55, 0, 0},
+	{Psalms, 56, 1, 0, Psalms, 56, 0, 0},
+	{Psalms, 57, 1, 0, Psalms, 57, 0, 0},
+	{Psalms, 58, 1, 0, Psalms, 58, 0, 0},
+	{Psalms, 59, 1, 0, Psalms, 59, 0, 0},
+	{Psalms, 60, 1, 2, Psalms, 60, 0, 0},
+	{Psalms, 61, 1, 0, Psalms, 61, 0, 0},
+	{Psalms, 62, 1, 0, Psalms, 62, 0, 0},
+	{Psalms, 63, 1, 0, Psalms, 63, 0, 0},
+	{Psalms, 64, 1, 0, Psalms, 64, 0, 0},
+	{Psalms, 65, 1, 0, Psalms, 65, 0, 0},
+	{Psalms, 67, 1, 0, Psalms, 67, 0, 0},
+	{Psalms, 68, 1, 0, Psalms, 68, 0, 0},
+	{Psalms, 69, 1, 0, Psalms, 69, 0, 0},
+	{Psalms, 70, 1, 0, Psalms, 70, 0, 0},
+	{Psalms, 75, 1, 0, Psalms, 75, 0, 0},
+	{Psalms, 76, 1, 0, Psalms, 76, 0, 0},
+	{Psalms, 77, 1, 0, Psalms, 77, 0, 0},
+	{Psalms, 80, 1, 0, Psalms, 80, 0, 0},
+	{Psalms, 81, 1, 0, Psalms, 81, 0, 0},
+	{Psalms, 83, 1, 0, Psalms, 83, 0, 0},
+	{Psalms, 84, 1, 0, Psalms, 84, 0, 0},
+	{Psalms, 85, 1, 0, Psalms, 85, 0, 0},
+	{Psalms, 88, 1, 0, Psalms, 88, 0, 0},
+	{Psalms, 89, 1, 0, Psalms, 89, 0, 0},
+	{Psalms, 92, 1, 0, Psalms, 92, 0, 0},
+	{Psalms, 102, 1, 0, Psalms, 102, 0, 0},
+	{Psalms, 108, 1, 0, Psalms, 108, 0, 0},
+	{Psalms, 140, 1, 0, Psalms, 140, 0, 0},
+	{Psalms, 142, 1, 0, Psalms, 142, 0, 0},
+	{Job, 40, 25, 0, Job, 41, 1, 0},
+	{Job, 41, 1, 0, Job, 41, 9, 0},
+	{Song, 7, 1, 0, Song, 6, 13, 0},
+	{Song, 7, 2, 0, Song, 7, 1, 0},
+	{Ecclesiastes, 4, 17, 0, Ecclesiastes, 5, 1, 0},
+	{Ecclesiastes, 5, 1, 0, Ecclesiastes, 5, 2, 0},
+	{Daniel, 3, 31, 0, Daniel, 4, 1, 0},
+	{Daniel, 4, 1, 0, Daniel, 4, 4, 0},
+	{Daniel, 6, 1, 0, Daniel, 5, 31, 0},
+	{Daniel, 6, 2, 0, Daniel, 6, 1, 0},
+	{Nehemiah, 3, 33, 0, Nehemiah, 4, 1, 0},
+	{Nehemiah, 4, 1, 0, Nehemiah, 4, 7, 0},
+	{Nehemiah, 7, 68, 0, Nehemiah, 7, 69, 0},
+	{Nehemiah, 10, 1, 0, Nehemiah, 9, 38, 0},
+	{Nehemiah, 10, 2, 0, Nehemiah, 10, 1, 0},
+	{Chronicles_1, 5, 27, 0, Chronicles_1, 6, 1, 0},
+	{Chronicles_1, 6, 1, 0, Chronicles_1, 6, 16, 0},
+	{Chronicles_1, 12, 4, 5, Chronicles_1, 12, 4, 0},
+	{Chronicles_1, 12, 6, 0, Chronicles_1, 12, 5, 0},
+	{Chronicles_2, 1, 18, 0, Chronicles_2, 2, 1, 0},
+	{Chronicles_2, 2, 1, 0, Chronicles_2, 2, 2, 0},
+	{Chronicles_2, 13, 23, 0, Chronicles_2, 14, 1, 0},
+	{Chronicles_2, 14, 1, 0, Chronicles_2, 14, 2, 0},
+}
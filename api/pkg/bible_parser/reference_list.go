@@ -0,0 +1,108 @@
+package verse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// listItem is a single comma or semicolon separated piece of a reference list
+// along with the separator that preceded it.
+type listItem struct {
+	text      string
+	separator rune
+}
+
+// splitReferenceList splits input on commas and semicolons. With
+// commaVerses a comma between two digits separates chapter from verse, as in
+// the German "Joh 3,16; 4,1", and is kept within the item.
+func splitReferenceList(input string, commaVerses bool) []listItem {
+	var items []listItem
+	separator := rune(0)
+	start := 0
+	for i, r := range input {
+		if r != ',' && r != ';' {
+			continue
+		}
+		if r == ',' && commaVerses && i > 0 && i+1 < len(input) && isDigit(input[i-1]) && isDigit(input[i+1]) {
+			continue
+		}
+		items = append(items, listItem{text: strings.TrimSpace(input[start:i]), separator: separator})
+		separator = r
+		start = i + 1
+	}
+	items = append(items, listItem{text: strings.TrimSpace(input[start:]), separator: separator})
+	return items
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+/**
+ * ParseReferenceList parses a list of references such as
+ * "Gen 1:1-3, 5, 7; 2:4-6; Ex 3" into an ordered slice of ranges.
+ *
+ * Items without a book carry over the book of the previous item. After a
+ * verse reference a comma continues with verses of the same chapter
+ * ("1:1-3, 5"), while a semicolon, or any item following a whole-chapter
+ * reference, starts a new chapter ("1:1; 2:4" or "Gen 1, 3").
+ */
+func ParseReferenceList(user_input string) ([]BibleReference, error) {
+	return parseReferenceList(user_input, parserLookup("en"), false)
+}
+
+// ParseReferenceListLocale is ParseReferenceList preferring the book names of
+// the given locale or version language. German lists use "3,16" for chapter
+// and verse and separate items with semicolons.
+func ParseReferenceListLocale(user_input string, locale string) ([]BibleReference, error) {
+	return parseReferenceList(user_input, parserLookup(locale), commaVerseLocale(locale))
+}
+
+func parseReferenceList(user_input string, lookup bookLookup, commaVerses bool) ([]BibleReference, error) {
+	var refs []BibleReference
+	var last *BibleReference
+
+	input := dashReplacer.Replace(user_input)
+	for _, item := range splitReferenceList(input, commaVerses) {
+		if item.text == "" {
+			continue
+		}
+
+		_, name, rest := splitBookName(item.text, lookup)
+		if name != "" {
+			ref, err := normalizeReference(item.text, lookup)
+			if err != nil {
+				re := err.(*ReferenceError)
+				return nil, &ReferenceError{Input: user_input, Msg: fmt.Sprintf("%s (item %q)", re.Msg, item.text), Suggestions: re.Suggestions}
+			}
+			refs = append(refs, ref)
+			last = &refs[len(refs)-1]
+			continue
+		}
+
+		if last == nil {
+			return nil, &ReferenceError{Input: user_input, Msg: fmt.Sprintf("missing book (item %q)", item.text)}
+		}
+
+		/* Continue within the previous chapter when the previous item was
+		 * verse level and the item does not name its own chapter.
+		 */
+		from, _, _ := strings.Cut(rest, "-")
+		explicitChapter := strings.ContainsAny(from, ":.,")
+		if item.separator == ',' && last.Granularity == GRANULARITY_VERSE && !explicitChapter {
+			rest = fmt.Sprintf("%d:%s", last.EndChapter, rest)
+		}
+
+		ref, err := parseRange(last.Book, rest, lookup)
+		if err != nil {
+			return nil, &ReferenceError{Input: user_input, Msg: fmt.Sprintf("%s (item %q)", err.Error(), item.text)}
+		}
+		refs = append(refs, ref)
+		last = &refs[len(refs)-1]
+	}
+
+	if len(refs) == 0 {
+		return nil, &ReferenceError{Input: user_input, Msg: "no references"}
+	}
+	return refs, nil
+}
@@ -0,0 +1,321 @@
+package verse
+
+import (
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Confidence rates how likely a scanned match is meant as a reference.
+type Confidence byte
+
+const (
+	// "Mark 2", "Is 2:30": a book name that is also an everyday word,
+	// without a verse or with one that reads as a time
+	CONFIDENCE_LOW Confidence = iota
+	// "Genesis 1": a chapter reference to an unambiguous book
+	CONFIDENCE_MEDIUM
+	// "Mark 2:1", "Gen 1:1-3, 5": a verse reference
+	CONFIDENCE_HIGH
+)
+
+func (c Confidence) String() string {
+	switch c {
+	case CONFIDENCE_LOW:
+		return "low"
+	case CONFIDENCE_MEDIUM:
+		return "medium"
+	case CONFIDENCE_HIGH:
+		return "high"
+	}
+	return "unknown"
+}
+
+// ReferenceMatch is a reference found in free-form text. Start and End are
+// byte offsets into the scanned text, so text[Start:End] == Text.
+type ReferenceMatch struct {
+	Start      int              `json:"start"`
+	End        int              `json:"end"`
+	Text       string           `json:"text"`
+	References []BibleReference `json:"references"`
+	Confidence Confidence       `json:"confidence"`
+}
+
+/**
+ * Scanner finds references in prose such as sermon notes, emails or
+ * markdown.
+ *
+ * Only English book names are recognized unless Locale names another
+ * language, in which case that language's names are tried first. When
+ * Versification is set, matches naming chapters or verses that do not exist
+ * in it ("Gen 51") are dropped.
+ */
+type Scanner struct {
+	Locale        string
+	Versification *Versification
+}
+
+// FindReferences scans text for references using English book names.
+func FindReferences(text string) []ReferenceMatch {
+	return Scanner{}.Find(text)
+}
+
+/*
+ * ambiguousBookNames are (normalized) book names and abbreviations that are
+ * also everyday words or given names: "Mark my words", "Job offer", "Acts 2
+ * and 3 of the play". A match on one of these needs a verse to be trusted.
+ */
+var ambiguousBookNames = map[string]bool{
+	"mark": true, "job": true, "acts": true, "act": true, "numbers": true,
+	"num": true, "judges": true, "kings": true, "lamentations": true,
+	"revelation": true, "song": true, "ruth": true, "john": true,
+	"james": true, "jude": true, "titus": true, "amos": true, "joel": true,
+	"daniel": true, "dan": true, "ex": true, "is": true, "am": true,
+	"mal": true, "col": true, "ps": true, "es": true, "gal": true,
+	"sam": true, "tim": true, "phil": true, "hab": true, "hag": true,
+	"jon": true, "jn": true, "mic": true, "nah": true, "lev": true,
+	"bar": true, "bel": true, "sus": true, "wisdom": true, "odes": true,
+	"wis": true, "tob": true, "man": true, "enoch": true,
+}
+
+var (
+	// A book name: an optional ordinal and up to four words ("Song of
+	// Songs", "1. Mose", "First John").
+	scanBookRe = regexp.MustCompile(`^(?:(?:[1-5](?:st|nd|rd|th)?\.?|(?i:iii|ii|iv|i|first|second|third|fourth)\.?)\s*)?\pL+(?:\.?[ \t]+\pL+){0,3}`)
+	// Word boundaries between the words of a book name.
+	scanWordRe = regexp.MustCompile(`\.?[ \t]+`)
+	// What may follow a book name before its chapter: "Gen 1", "Matt. 5",
+	// "Gen.1.1".
+	scanGapRe = regexp.MustCompile(`^(?:\.?[ \t]+|\.)`)
+	// A chapter and verse that could be a time of day: "2:30", "11:45".
+	scanTimeRe = regexp.MustCompile(`^(?:[01]?[0-9]|2[0-3]):[0-5][0-9]$`)
+)
+
+// lookup matches exact names only; prose is full of words that begin like
+// book names.
+func (s Scanner) lookup() bookLookup {
+	order := []*BookNames{}
+	if names, ok := bookNamesByLanguage[languageCode(s.Locale)]; ok && names != EnglishBookNames {
+		order = append(order, names)
+	}
+	order = append(order, EnglishBookNames)
+	return bookLookup{order: order, commaVerses: commaVerseLocale(s.Locale)}
+}
+
+/**
+ * Find returns every reference in text, in order of appearance. A match runs
+ * from the book name through any chapter and verse list that follows it
+ * ("Gen 1:1-3, 5; 2:4"); a new book name starts a new match.
+ */
+func (s Scanner) Find(text string) []ReferenceMatch {
+	lookup := s.lookup()
+	commaVerses := lookup.commaVerses
+
+	var matches []ReferenceMatch
+	for pos := 0; pos < len(text); {
+		r, size := utf8.DecodeRuneInString(text[pos:])
+		if !isWordStart(text, pos, r) {
+			pos += size
+			continue
+		}
+
+		if match, ok := s.matchAt(text, pos, lookup, commaVerses); ok {
+			matches = append(matches, match)
+			pos = match.End
+			continue
+		}
+
+		// Skip the rest of this word
+		for pos < len(text) {
+			r, size := utf8.DecodeRuneInString(text[pos:])
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				break
+			}
+			pos += size
+		}
+	}
+	return matches
+}
+
+// isWordStart reports whether a letter or digit at pos begins a word.
+func isWordStart(text string, pos int, r rune) bool {
+	if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+		return false
+	}
+	if pos == 0 {
+		return true
+	}
+	prev, _ := utf8.DecodeLastRuneInString(text[:pos])
+	return !unicode.IsLetter(prev) && !unicode.IsDigit(prev)
+}
+
+// matchBook matches the longest book name at pos that is followed by a
+// chapter number, returning the book and where the chapter list begins.
+func matchBook(text string, pos int, lookup bookLookup) (book Book, name string, numbers int, ok bool) {
+	candidate := scanBookRe.FindString(text[pos:])
+	if candidate == "" {
+		return 0, "", 0, false
+	}
+
+	// Try the longest run of words first: "Song of Songs" before "Song"
+	bounds := scanWordRe.FindAllStringIndex(candidate, -1)
+	ends := []int{len(candidate)}
+	for i := len(bounds) - 1; i >= 0; i-- {
+		ends = append(ends, bounds[i][0])
+	}
+
+	for _, end := range ends {
+		gap := scanGapRe.FindString(text[pos+end:])
+		if gap == "" {
+			continue
+		}
+		next := pos + end + len(gap)
+		if next >= len(text) || !isASCIIDigit(text[next]) {
+			continue
+		}
+
+		ordinal, bookName, _ := splitBookName(candidate[:end], lookup)
+		if bookName == "" {
+			continue
+		}
+		if b, found := lookup.find(ordinal + bookName); found {
+			return b, bookName, next, true
+		}
+	}
+	return 0, "", 0, false
+}
+
+func isASCIIDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// matchAt tries to match a reference starting at the word at pos.
+func (s Scanner) matchAt(text string, pos int, lookup bookLookup, commaVerses bool) (ReferenceMatch, bool) {
+	book, bookName, numbers, ok := matchBook(text, pos, lookup)
+	if !ok {
+		return ReferenceMatch{}, false
+	}
+
+	end, hasVerse := scanNumbers(text, numbers, book, lookup, commaVerses)
+	if end == numbers {
+		return ReferenceMatch{}, false
+	}
+
+	match := ReferenceMatch{Start: pos, End: end, Text: text[pos:end]}
+	refs, err := parseReferenceList(match.Text, lookup, commaVerses)
+	if err != nil {
+		return ReferenceMatch{}, false
+	}
+	if s.Versification != nil {
+		for _, ref := range refs {
+			if s.Versification.Validate(ref) != nil {
+				return ReferenceMatch{}, false
+			}
+		}
+	}
+	match.References = refs
+
+	first, _ := utf8.DecodeRuneInString(bookName)
+	lowercase := unicode.IsLower(first)
+	ambiguous := ambiguousBookNames[normalizeBookName(bookName)] || utf8.RuneCountInString(bookName) <= 2
+
+	switch {
+	case !hasVerse && ambiguous && lowercase:
+		// "is 5 minutes", "job 2 starts at noon"
+		return ReferenceMatch{}, false
+	case ambiguous && scanTimeRe.MatchString(text[numbers:end]):
+		// "Is 2:30 ok for you?"
+		match.Confidence = CONFIDENCE_LOW
+	case !hasVerse && ambiguous:
+		match.Confidence = CONFIDENCE_LOW
+	case !hasVerse || (ambiguous && lowercase):
+		match.Confidence = CONFIDENCE_MEDIUM
+	default:
+		match.Confidence = CONFIDENCE_HIGH
+	}
+	return match, true
+}
+
+/**
+ * scanNumbers consumes the chapter and verse list that starts at pos
+ * ("1:1-3, 5; 2:4") and returns where it ends and whether any item named a
+ * verse. A list item that begins a numbered book ("Gen 1:1, 2 Kings 3") ends
+ * the list.
+ */
+func scanNumbers(text string, pos int, book Book, lookup bookLookup, commaVerses bool) (end int, hasVerse bool) {
+	end, hasVerse, ok := scanRange(text, pos, book, lookup, commaVerses)
+	if !ok {
+		return pos, false
+	}
+
+	for {
+		i := skipSpace(text, end)
+		if i >= len(text) || (text[i] != ',' && text[i] != ';') {
+			return end, hasVerse
+		}
+		i = skipSpace(text, i+1)
+		if _, _, _, isBook := matchBook(text, i, lookup); isBook {
+			return end, hasVerse
+		}
+
+		next, verse, ok := scanRange(text, i, book, lookup, commaVerses)
+		if !ok {
+			return end, hasVerse
+		}
+		end, hasVerse = next, hasVerse || verse
+	}
+}
+
+// scanRange consumes "C", "C:V" or a range of those at pos, including OSIS
+// ranges that repeat the book ("Gen.1.1-Gen.1.3").
+func scanRange(text string, pos int, book Book, lookup bookLookup, commaVerses bool) (end int, hasVerse bool, ok bool) {
+	end, hasVerse, ok = scanChapterVerse(text, pos, commaVerses)
+	if !ok {
+		return pos, false, false
+	}
+
+	i := skipSpace(text, end)
+	if r, size := utf8.DecodeRuneInString(text[i:]); dashReplacer.Replace(string(r)) == "-" {
+		i = skipSpace(text, i+size)
+		if toBook, _, numbers, isBook := matchBook(text, i, lookup); isBook && toBook == book {
+			i = numbers
+		}
+		if to, verse, ok := scanChapterVerse(text, i, commaVerses); ok {
+			end, hasVerse = to, hasVerse || verse
+		}
+	}
+	return end, hasVerse, true
+}
+
+// scanChapterVerse consumes "C", "C:V" or "C.V" at pos, which must not run
+// on into a word ("2nd", "3pm").
+func scanChapterVerse(text string, pos int, commaVerses bool) (end int, hasVerse bool, ok bool) {
+	end = scanDigits(text, pos)
+	if end == pos {
+		return pos, false, false
+	}
+
+	if end+1 < len(text) && isASCIIDigit(text[end+1]) &&
+		(text[end] == ':' || text[end] == '.' || (commaVerses && text[end] == ',')) {
+		end, hasVerse = scanDigits(text, end+1), true
+	}
+
+	if r, _ := utf8.DecodeRuneInString(text[end:]); unicode.IsLetter(r) || unicode.IsDigit(r) {
+		return pos, false, false
+	}
+	return end, hasVerse, true
+}
+
+func scanDigits(text string, pos int) int {
+	for pos < len(text) && isASCIIDigit(text[pos]) {
+		pos++
+	}
+	return pos
+}
+
+func skipSpace(text string, pos int) int {
+	for pos < len(text) && (text[pos] == ' ' || text[pos] == '\t') {
+		pos++
+	}
+	return pos
+}
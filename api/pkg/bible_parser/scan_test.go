@@ -0,0 +1,57 @@
+package verse
+
+import "testing"
+
+func TestFindReferences(t *testing.T) {
+	tests := []struct {
+		text       string
+		match      string
+		confidence Confidence
+		refs       int
+	}{
+		{"Read Mark 2:1 tonight", "Mark 2:1", CONFIDENCE_HIGH, 1},
+		{"see Gen 1:1-3, 5; 2:4.", "Gen 1:1-3, 5; 2:4", CONFIDENCE_HIGH, 3},
+		{"Genesis 1 is the start", "Genesis 1", CONFIDENCE_MEDIUM, 1},
+		{"Acts 2 and 3 of the play", "Acts 2", CONFIDENCE_LOW, 1},
+		{"Isaiah 2:3", "Isaiah 2:3", CONFIDENCE_HIGH, 1},
+		{"Isa 2:30", "Isa 2:30", CONFIDENCE_HIGH, 1},
+		// Times of day after a book name that is also a word
+		{"Is 2:30 ok for you?", "Is 2:30", CONFIDENCE_LOW, 1},
+		{"Am 11:45 fine?", "Am 11:45", CONFIDENCE_LOW, 1},
+		{"Job 9:15 works", "Job 9:15", CONFIDENCE_LOW, 1},
+		{"Mark 2:30 then", "Mark 2:30", CONFIDENCE_LOW, 1},
+		{"Acts 2:1-4", "Acts 2:1-4", CONFIDENCE_HIGH, 1},
+	}
+	for _, tt := range tests {
+		matches := FindReferences(tt.text)
+		if len(matches) != 1 {
+			t.Errorf("%q: %d matches %+v, want 1", tt.text, len(matches), matches)
+			continue
+		}
+		m := matches[0]
+		if m.Text != tt.match || tt.text[m.Start:m.End] != m.Text {
+			t.Errorf("%q: matched %q at %d-%d, want %q", tt.text, m.Text, m.Start, m.End, tt.match)
+		}
+		if m.Confidence != tt.confidence {
+			t.Errorf("%q: confidence %s, want %s", tt.text, m.Confidence, tt.confidence)
+		}
+		if len(m.References) != tt.refs {
+			t.Errorf("%q: %d references, want %d", tt.text, len(m.References), tt.refs)
+		}
+	}
+}
+
+func TestFindReferencesFalsePositives(t *testing.T) {
+	for _, text := range []string{
+		"Mark my words",
+		"Job offer",
+		"It was a good job offer for 2 people",
+		"this is 5 minutes away",
+		"job 2 starts at noon",
+		"Revelation came at 3pm",
+	} {
+		if matches := FindReferences(text); len(matches) != 0 {
+			t.Errorf("%q: matches %+v, want none", text, matches)
+		}
+	}
+}
@@ -1,74 +1,114 @@
 package verse
 
-type Book
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Book int
 
 const (
-	Genesis 			Book = iota
-	Exodus 				Book
-	Leviticus 			Book
-	Numbers 			Book
-	Deuteronomy 		Book
-	Joshua 				Book
-	Judges 				Book
-	Ruth 				Book
-	Samuel_1 			Book
-	Samuel_2 			Book
-	Kings_1 			Book
-	Kings_2 			Book
-	Chronicles_1 		Book
-	Chronicles_2 		Book
-	Ezra 				Book
-	Nehemiah 			Book
-	Esther 				Book
-	Job 				Book
-	Psalms 				Book
-	Proverbs 			Book
-	Ecclesiastes 		Book
-	Song of Songs 		Book
-	Isaiah 				Book
-	Jeremiah 			Book
-	Lamentations 		Book
-	Ezekiel 			Book
-	Daniel 				Book
-	Hosea 				Book
-	Joel 				Book
-	Amos 				Book
-	Obadiah 			Book
-	Jonah 				Book
-	Micah 				Book
-	Nahum 				Book
-	Habakkuk 			Book
-	Zephaniah 			Book
-	Haggai 				Book
-	Zechariah 			Book
-	Malachi 			Book
-	Matthew 			Book
-	Mark 				Book
-	Luke 				Book
-	John 				Book
-	Acts 				Book
-	Romans 				Book
-	Corinthians_1 		Book
-	Corinthians_2 		Book
-	Galatians 			Book
-	Ephesians 			Book
-	Philippians 		Book
-	Colossians 			Book
-	Thessalonians_1 	Book
-	Thessalonians_2 	Book
-	Timothy_1 			Book
-	Timothy_2 			Book
-	Titus 				Book
-	Philemon 			Book
-	Hebrews 			Book
-	James 				Book
-	Peter_1 			Book
-	Peter_2 			Book
-	John_1 				Book
-	John_2 				Book
-	John_3 				Book
-	Jude 				Book
-	Revelation 			Book
+	Genesis Book = iota
+	Exodus
+	Leviticus
+	Numbers
+	Deuteronomy
+	Joshua
+	Judges
+	Ruth
+	Samuel_1
+	Samuel_2
+	Kings_1
+	Kings_2
+	Chronicles_1
+	Chronicles_2
+	Ezra
+	Nehemiah
+	Esther
+	Job
+	Psalms
+	Proverbs
+	Ecclesiastes
+	Song
+	Isaiah
+	Jeremiah
+	Lamentations
+	Ezekiel
+	Daniel
+	Hosea
+	Joel
+	Amos
+	Obadiah
+	Jonah
+	Micah
+	Nahum
+	Habakkuk
+	Zephaniah
+	Haggai
+	Zechariah
+	Malachi
+	Matthew
+	Mark
+	Luke
+	John
+	Acts
+	Romans
+	Corinthians_1
+	Corinthians_2
+	Galatians
+	Ephesians
+	Philippians
+	Colossians
+	Thessalonians_1
+	Thessalonians_2
+	Timothy_1
+	Timothy_2
+	Titus
+	Philemon
+	Hebrews
+	James
+	Peter_1
+	Peter_2
+	John_1
+	John_2
+	John_3
+	Jude
+	Revelation
+
+	/*
+	 * Deuterocanonical and apocryphal books follow the 66-book canon so that
+	 * the numbering of the others is stable. Tobit through Psalms of Solomon
+	 * match the Paratext book numbers (67-86). Which canons include each book
+	 * is recorded in canon.go.
+	 */
+	Tobit
+	Judith
+	EstherGreek
+	Wisdom
+	Sirach
+	Baruch
+	LetterJeremiah
+	SongThreeYouths
+	Susanna
+	Bel
+	Maccabees_1
+	Maccabees_2
+	Maccabees_3
+	Maccabees_4
+	Esdras_1
+	Esdras_2
+	Manasseh
+	Psalm151
+	Odes
+	PsalmsSolomon
+	DanielGreek
+	Enoch
+	Laodiceans
 )
 
 func (b Book) String() string {
@@ -116,7 +156,7 @@ func (b Book) String() string {
 	case Ecclesiastes:
 		return "Ecclesiastes"
 	case Song:
-		return "Song"
+		return "Song of Songs"
 	case Isaiah:
 		return "Isaiah"
 	case Jeremiah:
@@ -205,93 +245,490 @@ func (b Book) String() string {
 		return "Jude"
 	case Revelation:
 		return "Revelation"
+	case Tobit:
+		return "Tobit"
+	case Judith:
+		return "Judith"
+	case EstherGreek:
+		return "Esther (Greek)"
+	case Wisdom:
+		return "Wisdom of Solomon"
+	case Sirach:
+		return "Sirach"
+	case Baruch:
+		return "Baruch"
+	case LetterJeremiah:
+		return "Letter of Jeremiah"
+	case SongThreeYouths:
+		return "Song of the Three Young Men"
+	case Susanna:
+		return "Susanna"
+	case Bel:
+		return "Bel and the Dragon"
+	case Maccabees_1:
+		return "1 Maccabees"
+	case Maccabees_2:
+		return "2 Maccabees"
+	case Maccabees_3:
+		return "3 Maccabees"
+	case Maccabees_4:
+		return "4 Maccabees"
+	case Esdras_1:
+		return "1 Esdras"
+	case Esdras_2:
+		return "2 Esdras"
+	case Manasseh:
+		return "Prayer of Manasseh"
+	case Psalm151:
+		return "Psalm 151"
+	case Odes:
+		return "Odes"
+	case PsalmsSolomon:
+		return "Psalms of Solomon"
+	case DanielGreek:
+		return "Daniel (Greek)"
+	case Enoch:
+		return "1 Enoch"
+	case Laodiceans:
+		return "Laodiceans"
 	}
 	return "unknown"
 }
 
-Books := map[string]string{
-	"GEN" : "Genesis",
-	"EXO" : "Exodus",
-	"LEV" : "Leviticus",
-	"NUM" : "Numbers",
-	"DEU" : "Deuteronomy",
-	"JOS" : "Joshua",
-	"JDG" : "Judges",
-	"RUT" : "Ruth",
-	"1SA" : "1 Samuel",
-	"2SA" : "2 Samuel",
-	"1KI" : "1 Kings",
-	"2KI" : "2 Kings",
-	"1CH" : "1 Chronicles",
-	"2CH" : "2 Chronicles",
-	"EZR" : "Ezra",
-	"NEH" : "Nehemiah",
-	"EST" : "Esther",
-	"JOB" : "Job",
-	"PSA" : "Psalms",
-	"PRO" : "Proverbs",
-	"ECC" : "Ecclesiastes",
-	"SNG" : "Song of Songs",
-	"ISA" : "Isaiah",
-	"JER" : "Jeremiah",
-	"LAM" : "Lamentations",
-	"EZK" : "Ezekiel",
-	"DAN" : "Daniel",
-	"HOS" : "Hosea",
-	"JOL" : "Joel",
-	"AMO" : "Amos",
-	"OBA" : "Obadiah",
-	"JON" : "Jonah",
-	"MIC" : "Micah",
-	"NAM" : "Nahum",
-	"HAB" : "Habakkuk",
-	"ZEP" : "Zephaniah",
-	"HAG" : "Haggai",
-	"ZEC" : "Zechariah",
-	"MAL" : "Malachi",
-	"MAT" : "Matthew",
-	"MRK" : "Mark",
-	"LUK" : "Luke",
-	"JHN" : "John",
-	"ACT" : "Acts",
-	"ROM" : "Romans",
-	"1CO" : "1 Corinthians",
-	"2CO" : "2 Corinthians",
-	"GAL" : "Galatians",
-	"EPH" : "Ephesians",
-	"PHP" : "Philippians",
-	"COL" : "Colossians",
-	"1TH" : "1 Thessalonians",
-	"2TH" : "2 Thessalonians",
-	"1TI" : "1 Timothy",
-	"2TI" : "2 Timothy",
-	"TIT" : "Titus",
-	"PHM" : "Philemon",
-	"HEB" : "Hebrews",
-	"JAS" : "James",
-	"1PE" : "1 Peter",
-	"2PE" : "2 Peter",
-	"1JN" : "1 John",
-	"2JN" : "2 John",
-	"3JN" : "3 John",
-	"JUD" : "Jude",
-	"REV" : "Revelation",
+var Books = map[string]string{
+	"GEN": "Genesis",
+	"EXO": "Exodus",
+	"LEV": "Leviticus",
+	"NUM": "Numbers",
+	"DEU": "Deuteronomy",
+	"JOS": "Joshua",
+	"JDG": "Judges",
+	"RUT": "Ruth",
+	"1SA": "1 Samuel",
+	"2SA": "2 Samuel",
+	"1KI": "1 Kings",
+	"2KI": "2 Kings",
+	"1CH": "1 Chronicles",
+	"2CH": "2 Chronicles",
+	"EZR": "Ezra",
+	"NEH": "Nehemiah",
+	"EST": "Esther",
+	"JOB": "Job",
+	"PSA": "Psalms",
+	"PRO": "Proverbs",
+	"ECC": "Ecclesiastes",
+	"SNG": "Song of Songs",
+	"ISA": "Isaiah",
+	"JER": "Jeremiah",
+	"LAM": "Lamentations",
+	"EZK": "Ezekiel",
+	"DAN": "Daniel",
+	"HOS": "Hosea",
+	"JOL": "Joel",
+	"AMO": "Amos",
+	"OBA": "Obadiah",
+	"JON": "Jonah",
+	"MIC": "Micah",
+	"NAM": "Nahum",
+	"HAB": "Habakkuk",
+	"ZEP": "Zephaniah",
+	"HAG": "Haggai",
+	"ZEC": "Zechariah",
+	"MAL": "Malachi",
+	"MAT": "Matthew",
+	"MRK": "Mark",
+	"LUK": "Luke",
+	"JHN": "John",
+	"ACT": "Acts",
+	"ROM": "Romans",
+	"1CO": "1 Corinthians",
+	"2CO": "2 Corinthians",
+	"GAL": "Galatians",
+	"EPH": "Ephesians",
+	"PHP": "Philippians",
+	"COL": "Colossians",
+	"1TH": "1 Thessalonians",
+	"2TH": "2 Thessalonians",
+	"1TI": "1 Timothy",
+	"2TI": "2 Timothy",
+	"TIT": "Titus",
+	"PHM": "Philemon",
+	"HEB": "Hebrews",
+	"JAS": "James",
+	"1PE": "1 Peter",
+	"2PE": "2 Peter",
+	"1JN": "1 John",
+	"2JN": "2 John",
+	"3JN": "3 John",
+	"JUD": "Jude",
+	"REV": "Revelation",
+	"TOB": "Tobit",
+	"JDT": "Judith",
+	"ESG": "Esther (Greek)",
+	"WIS": "Wisdom of Solomon",
+	"SIR": "Sirach",
+	"BAR": "Baruch",
+	"LJE": "Letter of Jeremiah",
+	"S3Y": "Song of the Three Young Men",
+	"SUS": "Susanna",
+	"BEL": "Bel and the Dragon",
+	"1MA": "1 Maccabees",
+	"2MA": "2 Maccabees",
+	"3MA": "3 Maccabees",
+	"4MA": "4 Maccabees",
+	"1ES": "1 Esdras",
+	"2ES": "2 Esdras",
+	"MAN": "Prayer of Manasseh",
+	"PS2": "Psalm 151",
+	"ODA": "Odes",
+	"PSS": "Psalms of Solomon",
+	"DAG": "Daniel (Greek)",
+	"ENO": "1 Enoch",
+	"LAO": "Laodiceans",
+}
+
+// bookCodes holds the USFM code of every book, indexed by Book.
+var bookCodes = []string{
+	"GEN", "EXO", "LEV", "NUM", "DEU", "JOS", "JDG", "RUT", "1SA", "2SA",
+	"1KI", "2KI", "1CH", "2CH", "EZR", "NEH", "EST", "JOB", "PSA", "PRO",
+	"ECC", "SNG", "ISA", "JER", "LAM", "EZK", "DAN", "HOS", "JOL", "AMO",
+	"OBA", "JON", "MIC", "NAM", "HAB", "ZEP", "HAG", "ZEC", "MAL",
+	"MAT", "MRK", "LUK", "JHN", "ACT", "ROM", "1CO", "2CO", "GAL", "EPH",
+	"PHP", "COL", "1TH", "2TH", "1TI", "2TI", "TIT", "PHM", "HEB", "JAS",
+	"1PE", "2PE", "1JN", "2JN", "3JN", "JUD", "REV",
+	"TOB", "JDT", "ESG", "WIS", "SIR", "BAR", "LJE", "S3Y", "SUS", "BEL",
+	"1MA", "2MA", "3MA", "4MA", "1ES", "2ES", "MAN", "PS2", "ODA", "PSS",
+	"DAG", "ENO", "LAO",
+}
+
+// Valid reports whether b is one of the defined books.
+func (b Book) Valid() bool {
+	return b >= 0 && int(b) < len(bookCodes)
+}
+
+// Code returns the USFM code of the book (e.g. "GEN").
+func (b Book) Code() string {
+	if !b.Valid() {
+		return ""
+	}
+	return bookCodes[b]
 }
 
+// BookFromCode returns the book identified by the given USFM code.
+func BookFromCode(code string) (Book, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	for i, c := range bookCodes {
+		if c == code {
+			return Book(i), true
+		}
+	}
+	return 0, false
+}
 
+// MarshalText encodes the book as its USFM code.
+func (b Book) MarshalText() ([]byte, error) {
+	if !b.Valid() {
+		return nil, fmt.Errorf("invalid book %d", int(b))
+	}
+	return []byte(b.Code()), nil
+}
+
+// UnmarshalText decodes a book from its USFM code.
+func (b *Book) UnmarshalText(text []byte) error {
+	book, ok := BookFromCode(string(text))
+	if !ok {
+		return fmt.Errorf("unknown book code %q", string(text))
+	}
+	*b = book
+	return nil
+}
+
+// Granularity identifies how much of a book a BibleReference addresses.
+type Granularity byte
+
+const (
+	GRANULARITY_BOOK    Granularity = 0
+	GRANULARITY_CHAPTER Granularity = 1
+	GRANULARITY_VERSE   Granularity = 2
+)
+
+func (g Granularity) String() string {
+	switch g {
+	case GRANULARITY_BOOK:
+		return "book"
+	case GRANULARITY_CHAPTER:
+		return "chapter"
+	case GRANULARITY_VERSE:
+		return "verse"
+	}
+	return "unknown"
+}
+
+/**
+ * BibleReference is a single contiguous passage within one book.
+ *
+ * Chapter and verse numbers are 1-based. A zero verse means "the whole
+ * chapter" and a zero chapter means "the whole book", as indicated by
+ * Granularity.
+ */
 type BibleReference struct {
-	Book	string	`json:"name"`
+	Book         Book        `json:"book"`
+	StartChapter uint        `json:"start_chapter"`
+	StartVerse   uint        `json:"start_verse"`
+	EndChapter   uint        `json:"end_chapter"`
+	EndVerse     uint        `json:"end_verse"`
+	Granularity  Granularity `json:"granularity"`
+}
+
+// ReferenceError describes why a reference could not be understood.
+// Suggestions lists the books the user may have meant by an unknown name.
+type ReferenceError struct {
+	Input       string           `json:"input"`
+	Msg         string           `json:"msg"`
+	Suggestions []BookSuggestion `json:"suggestions,omitempty"`
+}
+
+func (re *ReferenceError) Error() string {
+	return fmt.Sprintf("%s in %q", re.Msg, re.Input)
+}
+
+// unknownBookError reports an unresolved book name along with suggestions.
+func unknownBookError(user_input string, name string, lookup bookLookup) *ReferenceError {
+	re := &ReferenceError{
+		Input:       user_input,
+		Msg:         fmt.Sprintf("unknown book %q", name),
+		Suggestions: lookup.suggest(name),
+	}
+
+	if len(re.Suggestions) > 0 {
+		var names []string
+		for i, s := range re.Suggestions {
+			if i == 3 {
+				break
+			}
+			names = append(names, s.Name)
+		}
+		re.Msg += fmt.Sprintf(", did you mean %s?", strings.Join(names, " or "))
+	}
+	return re
+}
+
+var (
+	// Leading ordinal of a numbered book: "1", "1st", "I ", "First ",
+	// "IV ", "5. Mose", "4 Царств".
+	ordinalDigitRe = regexp.MustCompile(`^([1-5])(?:st|nd|rd|th)?\.?\s*`)
+	ordinalWordRe  = regexp.MustCompile(`(?i)^(iii|ii|iv|i|first|second|third|fourth)(?:\.\s*|\s+)`)
+
+	dashReplacer = strings.NewReplacer("‐", "-", "‑", "-", "‒", "-", "–", "-", "—", "-")
+)
+
+var ordinalWords = map[string]string{
+	"i": "1", "ii": "2", "iii": "3", "iv": "4",
+	"first": "1", "second": "2", "third": "3", "fourth": "4",
+}
+
+/**
+ * splitBookName separates the book portion of a reference from the
+ * chapter/verse portion that follows it, returning the ordinal (if any) and
+ * the book name separately. The name ends at the first digit unless a longer
+ * name known to lookup holds digits of its own ("PS2 1", "Ps 151 1", "S3Y").
+ */
+func splitBookName(input string, lookup bookLookup) (ordinal string, name string, rest string) {
+	s := strings.TrimSpace(input)
+
+	// An ordinal only counts when a book name follows it ("1 Sam", not "1:1")
+	startsWithLetter := func(str string) bool {
+		r, _ := utf8.DecodeRuneInString(str)
+		return unicode.IsLetter(r)
+	}
+	if m := ordinalDigitRe.FindStringSubmatch(s); m != nil && startsWithLetter(s[len(m[0]):]) {
+		ordinal = m[1]
+		s = s[len(m[0]):]
+	} else if m := ordinalWordRe.FindStringSubmatch(s); m != nil && startsWithLetter(s[len(m[0]):]) {
+		ordinal = ordinalWords[strings.ToLower(m[1])]
+		s = s[len(m[0]):]
+	}
+
+	idx := strings.IndexFunc(s, unicode.IsDigit)
+	if idx < 0 {
+		idx = len(s)
+	}
+	for end := len(s); end > idx && ordinal == ""; end-- {
+		if end < len(s) && !strings.ContainsRune(". \t", rune(s[end])) {
+			continue
+		}
+		if _, ok := lookupNumberedIn(lookup.order, s[:end]); ok {
+			idx = end
+			break
+		}
+	}
+	name = strings.TrimRight(s[:idx], ". \t")
+	rest = strings.TrimSpace(strings.TrimLeft(s[idx:], "."))
+	return
 }
 
 /**
- * NormalizeVerseReference will take a string from the user and perform various
+ * parseChapterVerse parses "C", "C:V", "C.V" or, when commaVerses is set,
+ * the European "C,V". Elsewhere a comma starts another item of a list
+ * ("Gen 1:1-3, 5"), which only ParseReferenceList reads.
+ */
+func parseChapterVerse(s string, commaVerses bool) (chapter uint, verse uint, hasVerse bool, err error) {
+	if !commaVerses && strings.Contains(s, ",") {
+		return 0, 0, false, errors.New(`unexpected "," (lists of references are read by ParseReferenceList)`)
+	}
+	parts := strings.FieldsFunc(s, func(r rune) bool { return r == ':' || r == '.' || r == ',' })
+	if len(parts) < 1 || len(parts) > 2 {
+		return 0, 0, false, fmt.Errorf("invalid chapter/verse %q", s)
+	}
+
+	c, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil || c == 0 {
+		return 0, 0, false, fmt.Errorf("invalid chapter %q", parts[0])
+	}
+	if len(parts) == 1 {
+		return uint(c), 0, false, nil
+	}
+
+	v, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil || v == 0 {
+		return 0, 0, false, fmt.Errorf("invalid verse %q", parts[1])
+	}
+	return uint(c), uint(v), true, nil
+}
+
+/**
+ * NormalizeBibleReference will take a string from the user and perform various
  * transformations to attempt to understand what verse was being reference.
+ *
+ * Accepted forms include "Jn 3:16", "1st John 2:1-5", "I Sam 17",
+ * "Gen 1:1-2:3", "Gen 1-3", "Jude 5" and the dotted "ROM.8.28" or
+ * "Gen.1.1-Gen.1.3" forms. Book names may be given in any supported language
+ * ("Génesis 1:1", "Joh 3:16", "Мф 5:3"); the German "Joh 3,16" needs
+ * NormalizeBibleReferenceLocale, since a comma otherwise starts a list.
  */
-func NormalizeBibleReference(user_input string) {
+func NormalizeBibleReference(user_input string) (BibleReference, error) {
+	return normalizeReference(user_input, parserLookup("en"))
+}
+
+// NormalizeBibleReferenceLocale is NormalizeBibleReference preferring the
+// book names of the given locale or version language (see NamesForLocale).
+func NormalizeBibleReferenceLocale(user_input string, locale string) (BibleReference, error) {
+	return normalizeReference(user_input, parserLookup(locale))
+}
+
+func normalizeReference(user_input string, lookup bookLookup) (BibleReference, error) {
+	input := dashReplacer.Replace(user_input)
+
 	//Separate into book vs numbers
+	ordinal, name, rest := splitBookName(input, lookup)
+	if name == "" {
+		return BibleReference{}, &ReferenceError{Input: user_input, Msg: "missing book"}
+	}
 
 	// determine book reference to
+	book, ok := lookup.find(ordinal + name)
+	if !ok {
+		return BibleReference{}, unknownBookError(user_input, strings.TrimSpace(ordinal+" "+name), lookup)
+	}
 
 	//determine if numbers includes verse and/or chapter
+	ref, err := parseRange(book, rest, lookup)
+	if err != nil {
+		return BibleReference{}, &ReferenceError{Input: user_input, Msg: err.Error()}
+	}
+	return ref, nil
+}
+
+// parseRange interprets the chapter/verse portion of a reference to book,
+// resolving a repeated book name in the range end with lookup.
+func parseRange(book Book, rest string, lookup bookLookup) (BibleReference, error) {
+	ref := BibleReference{Book: book}
+
+	rest = strings.Join(strings.Fields(rest), "")
+	if rest == "" {
+		ref.Granularity = GRANULARITY_BOOK
+		return ref, nil
+	}
+
+	from, to, isRange := strings.Cut(rest, "-")
+	if isRange && strings.Contains(to, "-") {
+		return ref, errors.New("too many ranges")
+	}
+
+	fromChapter, fromVerse, fromHasVerse, err := parseChapterVerse(from, lookup.commaVerses)
+	if err != nil {
+		return ref, err
+	}
 
-}
\ No newline at end of file
+	var toChapter, toVerse uint
+	var toHasVerse bool
+	if isRange {
+		// OSIS style ranges repeat the book: "Gen.1.1-Gen.1.3"
+		toOrdinal, toName, toRest := splitBookName(to, lookup)
+		if toName != "" {
+			toBook, ok := lookup.find(toOrdinal + toName)
+			if !ok || toBook != book {
+				return ref, errors.New("ranges must stay within one book")
+			}
+			to = toRest
+		}
+
+		toChapter, toVerse, toHasVerse, err = parseChapterVerse(to, lookup.commaVerses)
+		if err != nil {
+			return ref, err
+		}
+	}
+
+	// "Jude 5" names a verse since the book only has a single chapter.
+	if book.SingleChapter() && !fromHasVerse && !toHasVerse {
+		fromChapter, fromVerse, fromHasVerse = 1, fromChapter, true
+		if isRange {
+			toChapter, toVerse, toHasVerse = 1, toChapter, true
+		}
+	}
+
+	ref.StartChapter = fromChapter
+	ref.StartVerse = fromVerse
+
+	switch {
+	case !isRange && !fromHasVerse:
+		// "Gen 1"
+		ref.Granularity = GRANULARITY_CHAPTER
+		ref.EndChapter = fromChapter
+	case !isRange:
+		// "Gen 1:1"
+		ref.Granularity = GRANULARITY_VERSE
+		ref.EndChapter = fromChapter
+		ref.EndVerse = fromVerse
+	case !fromHasVerse && !toHasVerse:
+		// "Gen 1-3"
+		ref.Granularity = GRANULARITY_CHAPTER
+		ref.EndChapter = toChapter
+	case fromHasVerse && !toHasVerse:
+		// "Gen 1:1-3"
+		ref.Granularity = GRANULARITY_VERSE
+		ref.EndChapter = fromChapter
+		ref.EndVerse = toChapter
+	case !fromHasVerse:
+		// "Gen 1-2:3"
+		ref.Granularity = GRANULARITY_VERSE
+		ref.StartVerse = 1
+		ref.EndChapter = toChapter
+		ref.EndVerse = toVerse
+	default:
+		// "Gen 1:1-2:3"
+		ref.Granularity = GRANULARITY_VERSE
+		ref.EndChapter = toChapter
+		ref.EndVerse = toVerse
+	}
+
+	/* A reversed range ("Gen 1:5-2") is most likely a typo, so it is
+	 * reported rather than read as some other passage.
+	 */
+	if ref.StartChapter > ref.EndChapter ||
+		(ref.StartChapter == ref.EndChapter && ref.StartVerse > ref.EndVerse) {
+		return ref, errors.New("the end of the range comes before its start")
+	}
+
+	return ref, nil
+}
@@ -0,0 +1,118 @@
+package verse
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+/**
+ * LoadVerseCountTables reads the verses per chapter tables of
+ * docs/resources/catholic-resources.org (ot-verses-*.tsv, nt-verses.tsv)
+ * into a scheme called name. Files that are not laid out as such a table,
+ * like the summaries next to them, are skipped.
+ */
+func LoadVerseCountTables(name string, paths ...string) (*Versification, error) {
+	v11n := &Versification{
+		Name:   name,
+		verses: map[Book][]uint{},
+	}
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		err = v11n.readVerseCountTable(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return v11n, nil
+}
+
+/**
+ * readVerseCountTable adds the books of a verse count table to the scheme.
+ * The OT tables have a row per book of "#, name, chapters, verses" followed
+ * by the verses of each chapter (Psalms takes three rows, only the last of
+ * which gives the chapters); the NT table has "name, chapters" followed by
+ * the verses of each chapter. Counts may be marked with an asterisk and
+ * chapters a book does not have are ".".
+ */
+func (v *Versification) readVerseCountTable(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	// The header tells the layout: where the name, chapters and counts are
+	nameColumn, chaptersColumn, firstCount := 0, 0, 0
+	if !scanner.Scan() {
+		return scanner.Err()
+	}
+	switch header := strings.Split(scanner.Text(), "\t"); strings.TrimSpace(header[0]) {
+	case "#":
+		nameColumn, chaptersColumn, firstCount = 1, 2, 4
+	case "Book":
+		nameColumn, chaptersColumn, firstCount = 0, 1, 2
+	default:
+		return nil
+	}
+
+	for line := 2; scanner.Scan(); line++ {
+		cells := strings.Split(scanner.Text(), "\t")
+		if len(cells) <= firstCount {
+			continue
+		}
+		book, ok := verseCountBook(cells[nameColumn])
+		if !ok {
+			// Column headers, totals
+			continue
+		}
+
+		chapters, err := verseCount(cells[chaptersColumn])
+		last := len(cells)
+		if err == nil && firstCount+int(chapters) < last {
+			last = firstCount + int(chapters)
+		}
+		var counts []uint
+		for _, cell := range cells[firstCount:last] {
+			count, err := verseCount(cell)
+			if err != nil {
+				break
+			}
+			counts = append(counts, count)
+		}
+
+		if _, ok := v.verses[book]; !ok {
+			v.books = append(v.books, book)
+		}
+		v.verses[book] = append(v.verses[book], counts...)
+		if chapters > 0 && uint(len(v.verses[book])) != chapters {
+			return fmt.Errorf("line %d: %s has %d chapters, found %d", line, book, chapters, len(v.verses[book]))
+		}
+	}
+	return scanner.Err()
+}
+
+// verseCountBook returns the book named in a verse count table, e.g.
+// "Ezekiel*", "Psalms (1-50)" or "Sirach/Ecclesiasticus".
+func verseCountBook(name string) (Book, bool) {
+	name = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(name), "*"))
+	if i := strings.Index(name, " ("); i >= 0 {
+		name = name[:i]
+	}
+	for _, alternative := range strings.Split(name, "/") {
+		if book, ok := LookupBook(strings.TrimSpace(alternative)); ok && alternative != "" {
+			return book, true
+		}
+	}
+	return 0, false
+}
+
+// verseCount reads a count of a verse count table, e.g. "53*".
+func verseCount(cell string) (uint, error) {
+	count, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimSpace(cell), "*"), 10, 32)
+	return uint(count), err
+}
@@ -0,0 +1,389 @@
+package verse
+
+import (
+	"fmt"
+	"sort"
+)
+
+/**
+ * VerseID is a canonical verse identifier packing the book, chapter and verse
+ * into one integer as BBCCCVVV, where BB is the 1-based book number. This is
+ * the same layout as the `id` column of the t_* tables read by cmd/importer,
+ * e.g. 1001001 is Genesis 1:1 and 66022021 is Revelation 22:21.
+ *
+ * IDs sort in the order of Book, so a passage within one book is a
+ * contiguous span of IDs. Schemes may order books differently, which Next,
+ * Previous and the range operations of Versification follow.
+ */
+type VerseID uint32
+
+// NewVerseID packs a book, chapter and verse into a VerseID.
+func NewVerseID(book Book, chapter uint, verse uint) VerseID {
+	return VerseID(uint32(book+1)*1000000 + uint32(chapter)*1000 + uint32(verse))
+}
+
+// Book returns the book of the verse.
+func (id VerseID) Book() Book {
+	return Book(id/1000000) - 1
+}
+
+// Chapter returns the chapter of the verse.
+func (id VerseID) Chapter() uint {
+	return uint(id / 1000 % 1000)
+}
+
+// Verse returns the verse number of the verse.
+func (id VerseID) Verse() uint {
+	return uint(id % 1000)
+}
+
+// Decode unpacks the book, chapter and verse of the verse.
+func (id VerseID) Decode() (Book, uint, uint) {
+	return id.Book(), id.Chapter(), id.Verse()
+}
+
+// Valid reports whether the ID names a known book and a non-zero chapter and
+// verse. Use Versification.Contains to check that the verse exists.
+func (id VerseID) Valid() bool {
+	return id.Book().Valid() && id.Chapter() > 0 && id.Verse() > 0
+}
+
+func (id VerseID) String() string {
+	return fmt.Sprintf("%s %d:%d", id.Book().Code(), id.Chapter(), id.Verse())
+}
+
+// Contains reports whether the verse exists within the scheme.
+func (v *Versification) Contains(id VerseID) bool {
+	book, chapter, verse := id.Decode()
+	return verse > 0 && verse <= v.VerseCount(book, chapter)
+}
+
+// bookIndex returns the position of book within the scheme's own order of
+// books.
+func (v *Versification) bookIndex(book Book) (int, bool) {
+	for i, b := range v.books {
+		if b == book {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// nextBook returns the book following book in the scheme's order, which
+// differs between traditions (KJVA places 1 Esdras after Malachi).
+func (v *Versification) nextBook(book Book) (Book, bool) {
+	i, ok := v.bookIndex(book)
+	if !ok || i+1 >= len(v.books) {
+		return 0, false
+	}
+	return v.books[i+1], true
+}
+
+// previousBook returns the book preceding book in the scheme's order.
+func (v *Versification) previousBook(book Book) (Book, bool) {
+	i, ok := v.bookIndex(book)
+	if !ok || i == 0 {
+		return 0, false
+	}
+	return v.books[i-1], true
+}
+
+/**
+ * Next returns the verse following id, moving on to the next chapter or book
+ * (in the scheme's order) as needed. It returns false at the end of the last
+ * book or if id does not exist within the scheme.
+ */
+func (v *Versification) Next(id VerseID) (VerseID, bool) {
+	if !v.Contains(id) {
+		return 0, false
+	}
+
+	book, chapter, verse := id.Decode()
+	switch {
+	case verse < v.VerseCount(book, chapter):
+		return NewVerseID(book, chapter, verse+1), true
+	case chapter < v.ChapterCount(book):
+		return NewVerseID(book, chapter+1, 1), true
+	}
+
+	next, ok := v.nextBook(book)
+	if !ok {
+		return 0, false
+	}
+	return NewVerseID(next, 1, 1), true
+}
+
+/**
+ * Previous returns the verse preceding id, moving back to the previous
+ * chapter or book (in the scheme's order) as needed. It returns false at the
+ * start of the first book or if id does not exist within the scheme.
+ */
+func (v *Versification) Previous(id VerseID) (VerseID, bool) {
+	if !v.Contains(id) {
+		return 0, false
+	}
+
+	book, chapter, verse := id.Decode()
+	switch {
+	case verse > 1:
+		return NewVerseID(book, chapter, verse-1), true
+	case chapter > 1:
+		return NewVerseID(book, chapter-1, v.VerseCount(book, chapter-1)), true
+	}
+
+	previous, ok := v.previousBook(book)
+	if !ok {
+		return 0, false
+	}
+	last := v.ChapterCount(previous)
+	return NewVerseID(previous, last, v.VerseCount(previous, last)), true
+}
+
+// ordinal returns the 1-based position of id counting every verse of the
+// scheme in the scheme's order of books.
+func (v *Versification) ordinal(id VerseID) uint {
+	book, chapter, verse := id.Decode()
+
+	var position uint
+	for _, b := range v.books {
+		if b == book {
+			break
+		}
+		position += v.BookVerseCount(b)
+	}
+	for c := uint(1); c < chapter; c++ {
+		position += v.VerseCount(book, c)
+	}
+	return position + verse
+}
+
+// position returns the ordinal of id, failing for verses the scheme does
+// not have.
+func (v *Versification) position(id VerseID) (uint, error) {
+	if !v.Contains(id) {
+		return 0, fmt.Errorf("%s does not exist (%s versification)", id, v.Name)
+	}
+	return v.ordinal(id), nil
+}
+
+/**
+ * CountVerses returns the number of verses from a through b inclusive, in
+ * either order.
+ */
+func (v *Versification) CountVerses(a VerseID, b VerseID) (uint, error) {
+	r, err := v.span(VerseRange{Start: a, End: b})
+	if err != nil {
+		return 0, err
+	}
+	return r.last - r.first + 1, nil
+}
+
+/**
+ * VerseRange is an inclusive span of verses. A span crossing books runs in
+ * the order of a scheme's books, which is not always the order of their IDs
+ * (KJVA places 1 Esdras after Malachi), so ranges are compared through the
+ * Versification they belong to.
+ */
+type VerseRange struct {
+	Start VerseID `json:"start"`
+	End   VerseID `json:"end"`
+}
+
+func (r VerseRange) String() string {
+	return fmt.Sprintf("%s-%s", r.Start, r.End)
+}
+
+// schemeSpan is a range with the ordinals of its first and last verses.
+type schemeSpan struct {
+	VerseRange
+	first, last uint
+}
+
+// span positions r within the scheme, swapping its ends if they come in
+// the wrong order.
+func (v *Versification) span(r VerseRange) (schemeSpan, error) {
+	first, err := v.position(r.Start)
+	if err != nil {
+		return schemeSpan{}, err
+	}
+	last, err := v.position(r.End)
+	if err != nil {
+		return schemeSpan{}, err
+	}
+	if first > last {
+		r.Start, r.End, first, last = r.End, r.Start, last, first
+	}
+	return schemeSpan{VerseRange: r, first: first, last: last}, nil
+}
+
+// NewVerseRange returns the range between two verses, in either order.
+func (v *Versification) NewVerseRange(a VerseID, b VerseID) (VerseRange, error) {
+	r, err := v.span(VerseRange{Start: a, End: b})
+	return r.VerseRange, err
+}
+
+// RangeContains reports whether id falls within r.
+func (v *Versification) RangeContains(r VerseRange, id VerseID) (bool, error) {
+	span, err := v.span(r)
+	if err != nil {
+		return false, err
+	}
+	position, err := v.position(id)
+	if err != nil {
+		return false, err
+	}
+	return position >= span.first && position <= span.last, nil
+}
+
+// ContainsRange reports whether o falls entirely within r.
+func (v *Versification) ContainsRange(r VerseRange, o VerseRange) (bool, error) {
+	a, b, err := v.spans(r, o)
+	if err != nil {
+		return false, err
+	}
+	return b.first >= a.first && b.last <= a.last, nil
+}
+
+// Overlaps reports whether the ranges share at least one verse.
+func (v *Versification) Overlaps(r VerseRange, o VerseRange) (bool, error) {
+	a, b, err := v.spans(r, o)
+	if err != nil {
+		return false, err
+	}
+	return a.overlaps(b), nil
+}
+
+func (a schemeSpan) overlaps(b schemeSpan) bool {
+	return a.first <= b.last && b.first <= a.last
+}
+
+// Intersect returns the verses shared by both ranges, or false if they do
+// not overlap.
+func (v *Versification) Intersect(r VerseRange, o VerseRange) (VerseRange, bool, error) {
+	a, b, err := v.spans(r, o)
+	if err != nil || !a.overlaps(b) {
+		return VerseRange{}, false, err
+	}
+	res := a.VerseRange
+	if b.first > a.first {
+		res.Start = b.Start
+	}
+	if b.last < a.last {
+		res.End = b.End
+	}
+	return res, true, nil
+}
+
+func (v *Versification) spans(r VerseRange, o VerseRange) (schemeSpan, schemeSpan, error) {
+	a, err := v.span(r)
+	if err != nil {
+		return schemeSpan{}, schemeSpan{}, err
+	}
+	b, err := v.span(o)
+	return a, b, err
+}
+
+/**
+ * Range converts a reference into a VerseRange, filling in the verses of
+ * whole books and chapters from the scheme.
+ */
+func (v *Versification) Range(ref BibleReference) (VerseRange, error) {
+	resolved, err := v.Resolve(ref)
+	if err != nil {
+		return VerseRange{}, err
+	}
+	return VerseRange{
+		Start: NewVerseID(resolved.Book, resolved.StartChapter, resolved.StartVerse),
+		End:   NewVerseID(resolved.Book, resolved.EndChapter, resolved.EndVerse),
+	}, nil
+}
+
+/**
+ * Reference converts a range back into a verse level reference. Ranges that
+ * span books have no single reference and return an error.
+ */
+func (r VerseRange) Reference() (BibleReference, error) {
+	if r.Start.Book() != r.End.Book() {
+		return BibleReference{}, fmt.Errorf("range %s spans books", r)
+	}
+	return BibleReference{
+		Book:         r.Start.Book(),
+		StartChapter: r.Start.Chapter(),
+		StartVerse:   r.Start.Verse(),
+		EndChapter:   r.End.Chapter(),
+		EndVerse:     r.End.Verse(),
+		Granularity:  GRANULARITY_VERSE,
+	}, nil
+}
+
+/**
+ * Union merges the given ranges into the fewest non-overlapping ranges,
+ * sorted in the scheme's order. Ranges that touch (Gen 1:31 followed by
+ * Gen 2:1) are merged as well.
+ */
+func (v *Versification) Union(ranges ...VerseRange) ([]VerseRange, error) {
+	if len(ranges) == 0 {
+		return nil, nil
+	}
+
+	sorted := make([]schemeSpan, len(ranges))
+	for i, r := range ranges {
+		span, err := v.span(r)
+		if err != nil {
+			return nil, err
+		}
+		sorted[i] = span
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].first < sorted[j].first
+	})
+
+	merged := []schemeSpan{sorted[0]}
+	for _, r := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if r.first <= last.last+1 {
+			if r.last > last.last {
+				last.End, last.last = r.End, r.last
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	res := make([]VerseRange, len(merged))
+	for i, span := range merged {
+		res[i] = span.VerseRange
+	}
+	return res, nil
+}
+
+/**
+ * Subtract removes the verses of o from r, returning what remains: nothing,
+ * one range, or two ranges when o falls in the middle of r.
+ */
+func (v *Versification) Subtract(r VerseRange, o VerseRange) ([]VerseRange, error) {
+	a, b, err := v.spans(r, o)
+	if err != nil {
+		return nil, err
+	}
+	if !a.overlaps(b) {
+		return []VerseRange{a.VerseRange}, nil
+	}
+
+	var res []VerseRange
+	if b.first > a.first {
+		end, ok := v.Previous(b.Start)
+		if !ok {
+			return nil, fmt.Errorf("no verse before %s (%s versification)", b.Start, v.Name)
+		}
+		res = append(res, VerseRange{Start: a.Start, End: end})
+	}
+	if b.last < a.last {
+		start, ok := v.Next(b.End)
+		if !ok {
+			return nil, fmt.Errorf("no verse after %s (%s versification)", b.End, v.Name)
+		}
+		res = append(res, VerseRange{Start: start, End: a.End})
+	}
+	return res, nil
+}
@@ -0,0 +1,132 @@
+package verse
+
+import (
+	"reflect"
+	"testing"
+)
+
+const versificationPath = "../../../versification/sword-1.9.0-versification.json"
+
+func loadScheme(t *testing.T, name string) *Versification {
+	t.Helper()
+	schemes, err := LoadVersifications(versificationPath)
+	if err != nil {
+		t.Skipf("versifications: %v", err)
+	}
+	v, ok := schemes[name]
+	if !ok {
+		t.Fatalf("no %s versification", name)
+	}
+	return v
+}
+
+func TestCountVerses(t *testing.T) {
+	kjv, kjva := loadScheme(t, "KJV"), loadScheme(t, "KJVA")
+	tests := []struct {
+		v    *Versification
+		a, b VerseID
+		want uint
+	}{
+		{kjv, NewVerseID(Genesis, 1, 1), NewVerseID(Genesis, 1, 31), 31},
+		{kjv, NewVerseID(Genesis, 1, 31), NewVerseID(Genesis, 1, 1), 31},
+		{kjv, NewVerseID(Genesis, 1, 31), NewVerseID(Genesis, 2, 1), 2},
+		{kjv, NewVerseID(Malachi, 4, 6), NewVerseID(Matthew, 1, 1), 2},
+		// KJVA puts the Apocrypha between the testaments
+		{kjva, NewVerseID(Malachi, 4, 6), NewVerseID(Matthew, 1, 1), 5719},
+	}
+	for _, tt := range tests {
+		got, err := tt.v.CountVerses(tt.a, tt.b)
+		if err != nil {
+			t.Errorf("%s %s-%s: %v", tt.v.Name, tt.a, tt.b, err)
+		} else if got != tt.want {
+			t.Errorf("%s %s-%s = %d, want %d", tt.v.Name, tt.a, tt.b, got, tt.want)
+		}
+	}
+	if _, err := kjv.CountVerses(NewVerseID(Genesis, 1, 1), NewVerseID(Tobit, 1, 1)); err == nil {
+		t.Errorf("KJV has no Tobit, want an error")
+	}
+}
+
+func TestVerseRangeSchemeOrder(t *testing.T) {
+	kjva := loadScheme(t, "KJVA")
+	mal, mt := NewVerseID(Malachi, 4, 6), NewVerseID(Matthew, 1, 1)
+	tobit, esdras := NewVerseID(Tobit, 1, 1), NewVerseID(Esdras_1, 1, 1)
+	r, err := kjva.NewVerseRange(mt, mal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Start != mal || r.End != mt {
+		t.Errorf("NewVerseRange = %s, want %s first", r, mal)
+	}
+	if ok, err := kjva.RangeContains(r, tobit); err != nil || !ok {
+		t.Errorf("KJVA %s contains Tobit = %v, %v, want true", r, ok, err)
+	}
+	if ok, err := kjva.RangeContains(VerseRange{Start: mal, End: NewVerseID(Malachi, 4, 6)}, tobit); err != nil || ok {
+		t.Errorf("Mal 4:6 contains Tobit = %v, %v, want false", ok, err)
+	}
+
+	// Malachi touches 1 Esdras in KJVA, Matthew does not
+	merged, err := kjva.Union(
+		VerseRange{Start: NewVerseID(Malachi, 4, 1), End: mal},
+		VerseRange{Start: esdras, End: NewVerseID(Esdras_1, 1, 5)},
+		VerseRange{Start: mt, End: NewVerseID(Matthew, 1, 2)},
+	)
+	want := []VerseRange{
+		{Start: NewVerseID(Malachi, 4, 1), End: NewVerseID(Esdras_1, 1, 5)},
+		{Start: mt, End: NewVerseID(Matthew, 1, 2)},
+	}
+	if err != nil || !reflect.DeepEqual(merged, want) {
+		t.Errorf("Union = %v, %v, want %v", merged, err, want)
+	}
+
+	// Subtracting the Apocrypha leaves the ends of the testaments
+	left, err := kjva.Subtract(VerseRange{Start: mal, End: mt},
+		VerseRange{Start: esdras, End: NewVerseID(Matthew, 1, 1)})
+	want = []VerseRange{{Start: mal, End: mal}}
+	if err != nil || !reflect.DeepEqual(left, want) {
+		t.Errorf("Subtract = %v, %v, want %v", left, err, want)
+	}
+}
+
+func TestVerseRangeArithmetic(t *testing.T) {
+	kjv := loadScheme(t, "KJV")
+	gen := func(chapter, verse uint) VerseID { return NewVerseID(Genesis, chapter, verse) }
+
+	merged, err := kjv.Union(
+		VerseRange{Start: gen(2, 1), End: gen(2, 3)},
+		VerseRange{Start: gen(1, 1), End: gen(1, 31)},
+		VerseRange{Start: gen(3, 5), End: gen(3, 1)},
+	)
+	want := []VerseRange{{Start: gen(1, 1), End: gen(2, 3)}, {Start: gen(3, 1), End: gen(3, 5)}}
+	if err != nil || !reflect.DeepEqual(merged, want) {
+		t.Errorf("Union = %v, %v, want %v", merged, err, want)
+	}
+
+	r := VerseRange{Start: gen(1, 1), End: gen(2, 25)}
+	left, err := kjv.Subtract(r, VerseRange{Start: gen(1, 31), End: gen(2, 1)})
+	want = []VerseRange{{Start: gen(1, 1), End: gen(1, 30)}, {Start: gen(2, 2), End: gen(2, 25)}}
+	if err != nil || !reflect.DeepEqual(left, want) {
+		t.Errorf("Subtract = %v, %v, want %v", left, err, want)
+	}
+	if left, err := kjv.Subtract(r, VerseRange{Start: gen(3, 1), End: gen(3, 2)}); err != nil || !reflect.DeepEqual(left, []VerseRange{r}) {
+		t.Errorf("Subtract disjoint = %v, %v, want %v", left, err, r)
+	}
+	if left, err := kjv.Subtract(r, r); err != nil || len(left) != 0 {
+		t.Errorf("Subtract all = %v, %v, want nothing", left, err)
+	}
+	// Verses the scheme does not have are errors, not dropped remainders
+	if left, err := kjv.Subtract(r, VerseRange{Start: gen(1, 40), End: gen(2, 1)}); err == nil {
+		t.Errorf("Subtract Gen 1:40 = %v, want an error", left)
+	}
+
+	if got, ok, err := kjv.Intersect(r, VerseRange{Start: gen(2, 20), End: gen(3, 3)}); err != nil || !ok ||
+		got != (VerseRange{Start: gen(2, 20), End: gen(2, 25)}) {
+		t.Errorf("Intersect = %v, %v, %v", got, ok, err)
+	}
+	if ok, err := kjv.ContainsRange(r, VerseRange{Start: gen(1, 5), End: gen(2, 1)}); err != nil || !ok {
+		t.Errorf("ContainsRange = %v, %v, want true", ok, err)
+	}
+	if ok, err := kjv.Overlaps(r, VerseRange{Start: gen(3, 1), End: gen(3, 2)}); err != nil || ok {
+		t.Errorf("Overlaps = %v, %v, want false", ok, err)
+	}
+}
@@ -0,0 +1,98 @@
+package verse
+
+import "testing"
+
+func verseRef(book Book, startChapter, startVerse, endChapter, endVerse uint) BibleReference {
+	return BibleReference{Book: book, StartChapter: startChapter, StartVerse: startVerse,
+		EndChapter: endChapter, EndVerse: endVerse, Granularity: GRANULARITY_VERSE}
+}
+
+func chapterRef(book Book, startChapter, endChapter uint) BibleReference {
+	return BibleReference{Book: book, StartChapter: startChapter, EndChapter: endChapter, Granularity: GRANULARITY_CHAPTER}
+}
+
+func TestNormalizeBibleReference(t *testing.T) {
+	tests := []struct {
+		input  string
+		locale string
+		want   BibleReference
+	}{
+		{"Gen 1:1", "en", verseRef(Genesis, 1, 1, 1, 1)},
+		{"Gen 1:1-3", "en", verseRef(Genesis, 1, 1, 1, 3)},
+		{"Gen 1:31-2:3", "en", verseRef(Genesis, 1, 31, 2, 3)},
+		{"Gen 1-3", "en", chapterRef(Genesis, 1, 3)},
+		{"Gen.1.1-Gen.1.3", "en", verseRef(Genesis, 1, 1, 1, 3)},
+		{"Jude 5", "en", verseRef(Jude, 1, 5, 1, 5)},
+		{"Gen", "en", BibleReference{Book: Genesis, Granularity: GRANULARITY_BOOK}},
+		{"1. Mose 1,1", "de", verseRef(Genesis, 1, 1, 1, 1)},
+		{"Joh 3,16-18", "de", verseRef(John, 3, 16, 3, 18)},
+		{"Gen 1:1", "de", verseRef(Genesis, 1, 1, 1, 1)},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeBibleReferenceLocale(tt.input, tt.locale)
+		if err != nil {
+			t.Errorf("%q (%s): %v", tt.input, tt.locale, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q (%s) = %+v, want %+v", tt.input, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeBibleReferenceErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		locale string
+	}{
+		// Reversed ranges are typos, not other passages
+		{"Gen 1:5-2", "en"},
+		{"Gen 3-1", "en"},
+		{"Gen 2:3-1:4", "en"},
+		{"Jude 5-3", "en"},
+		// A comma only separates chapter and verse in comma verse locales
+		{"Gen 1:1-3,5", "en"},
+		{"Joh 3,16", "en"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeBibleReferenceLocale(tt.input, tt.locale)
+		if err == nil {
+			t.Errorf("%q (%s) = %+v, want an error", tt.input, tt.locale, got)
+			continue
+		}
+		if _, ok := err.(*ReferenceError); !ok {
+			t.Errorf("%q (%s): error %T, want *ReferenceError", tt.input, tt.locale, err)
+		}
+	}
+}
+
+func TestParseReferenceList(t *testing.T) {
+	tests := []struct {
+		input  string
+		locale string
+		want   []BibleReference
+	}{
+		{"Gen 1:1-3, 5", "en", []BibleReference{verseRef(Genesis, 1, 1, 1, 3), verseRef(Genesis, 1, 5, 1, 5)}},
+		{"1. Mose 1,1-3; 2,4", "de", []BibleReference{verseRef(Genesis, 1, 1, 1, 3), verseRef(Genesis, 2, 4, 2, 4)}},
+	}
+	for _, tt := range tests {
+		got, err := ParseReferenceListLocale(tt.input, tt.locale)
+		if err != nil {
+			t.Errorf("%q (%s): %v", tt.input, tt.locale, err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q (%s) = %+v, want %+v", tt.input, tt.locale, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%q (%s) item %d = %+v, want %+v", tt.input, tt.locale, i, got[i], tt.want[i])
+			}
+		}
+	}
+
+	if refs, err := ParseReferenceList("Gen 1:1, 5-2"); err == nil {
+		t.Errorf("reversed list item = %+v, want an error", refs)
+	}
+}
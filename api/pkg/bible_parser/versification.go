@@ -0,0 +1,249 @@
+package verse
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+/**
+ * Versification describes how one versification scheme (KJV, Synodal,
+ * Vulgate, ...) divides the books it contains into chapters and verses.
+ *
+ * Schemes are loaded from the JSON generated from the Sword sources, see
+ * versification/README.md. The order of books within a scheme is preserved
+ * since it differs between traditions.
+ */
+type Versification struct {
+	Name     string
+	books    []Book
+	verses   map[Book][]uint
+	mappings []verseMapping
+}
+
+// VersificationError reports a reference that does not exist in a scheme.
+type VersificationError struct {
+	Scheme string         `json:"scheme"`
+	Ref    BibleReference `json:"ref"`
+	Msg    string         `json:"msg"`
+}
+
+func (ve *VersificationError) Error() string {
+	return fmt.Sprintf("%s (%s versification)", ve.Msg, ve.Scheme)
+}
+
+/**
+ * LoadVersifications reads every scheme within the versification JSON file at
+ * path (e.g. versification/sword-1.9.0-versification.json), keyed by scheme
+ * name.
+ */
+func LoadVersifications(path string) (map[string]*Versification, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadVersifications(file)
+}
+
+// ReadVersifications reads every scheme from versification JSON.
+func ReadVersifications(r io.Reader) (map[string]*Versification, error) {
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	schemes := map[string]*Versification{}
+	for dec.More() {
+		name, err := readKey(dec)
+		if err != nil {
+			return nil, err
+		}
+
+		v11n := &Versification{
+			Name:   name,
+			verses: map[Book][]uint{},
+		}
+
+		// Decode book by book so the order of the books is kept
+		if err := expectDelim(dec, '{'); err != nil {
+			return nil, err
+		}
+		for dec.More() {
+			osis, err := readKey(dec)
+			if err != nil {
+				return nil, err
+			}
+			var counts []uint
+			if err := dec.Decode(&counts); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", name, osis, err)
+			}
+
+			book, ok := BookFromOSIS(osis)
+			if !ok {
+				// A book we do not model
+				continue
+			}
+			v11n.books = append(v11n.books, book)
+			v11n.verses[book] = counts
+		}
+		if err := expectDelim(dec, '}'); err != nil {
+			return nil, err
+		}
+
+		if hebrewSchemes[name] {
+			v11n.mappings = append([]verseMapping(nil), hebrewMappings...)
+		}
+		schemes[name] = v11n
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, err
+	}
+	return schemes, nil
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("versification: expected %q, found %v", delim, tok)
+	}
+	return nil
+}
+
+func readKey(dec *json.Decoder) (string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("versification: expected a key, found %v", tok)
+	}
+	return key, nil
+}
+
+// SchemeNames returns the sorted names of the given schemes.
+func SchemeNames(schemes map[string]*Versification) []string {
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Books returns the books of the scheme in the scheme's canonical order.
+func (v *Versification) Books() []Book {
+	return append([]Book(nil), v.books...)
+}
+
+// HasBook reports whether the scheme includes book.
+func (v *Versification) HasBook(book Book) bool {
+	_, ok := v.verses[book]
+	return ok
+}
+
+// ChapterCount returns the number of chapters of book, or 0 if the scheme
+// does not include it.
+func (v *Versification) ChapterCount(book Book) uint {
+	return uint(len(v.verses[book]))
+}
+
+// VerseCount returns the number of verses in the given chapter of book, or 0
+// if the chapter does not exist.
+func (v *Versification) VerseCount(book Book, chapter uint) uint {
+	counts := v.verses[book]
+	if chapter < 1 || chapter > uint(len(counts)) {
+		return 0
+	}
+	return counts[chapter-1]
+}
+
+// VerseCounts returns the number of verses of every chapter of book, indexed
+// by chapter - 1.
+func (v *Versification) VerseCounts(book Book) []uint {
+	return append([]uint(nil), v.verses[book]...)
+}
+
+// BookVerseCount returns the total number of verses in book.
+func (v *Versification) BookVerseCount(book Book) uint {
+	var total uint
+	for _, count := range v.verses[book] {
+		total += count
+	}
+	return total
+}
+
+/**
+ * Validate checks that every chapter and verse named by ref exists within
+ * the scheme, e.g. "Genesis 51" or "Jude 2:1" fail.
+ */
+func (v *Versification) Validate(ref BibleReference) error {
+	fail := func(format string, args ...interface{}) error {
+		return &VersificationError{Scheme: v.Name, Ref: ref, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	if !v.HasBook(ref.Book) {
+		return fail("%s is not part of this versification", ref.Book)
+	}
+	if ref.Granularity == GRANULARITY_BOOK {
+		return nil
+	}
+
+	chapters := v.ChapterCount(ref.Book)
+	for _, chapter := range []uint{ref.StartChapter, ref.EndChapter} {
+		if chapter < 1 || chapter > chapters {
+			return fail("%s has %d chapter%s, chapter %d does not exist", ref.Book, chapters, plural(chapters), chapter)
+		}
+	}
+	if ref.Granularity == GRANULARITY_CHAPTER {
+		return nil
+	}
+
+	points := [][2]uint{{ref.StartChapter, ref.StartVerse}, {ref.EndChapter, ref.EndVerse}}
+	for _, point := range points {
+		verses := v.VerseCount(ref.Book, point[0])
+		if point[1] < 1 || point[1] > verses {
+			return fail("%s %d has %d verse%s, verse %d does not exist", ref.Book, point[0], verses, plural(verses), point[1])
+		}
+	}
+	return nil
+}
+
+/**
+ * Resolve validates ref and fills in the verses implied by a book or chapter
+ * reference, so that "Gen 1" becomes Gen 1:1-31 and "Jude" Jude 1:1-25. The
+ * granularity of the reference is kept.
+ */
+func (v *Versification) Resolve(ref BibleReference) (BibleReference, error) {
+	if err := v.Validate(ref); err != nil {
+		return ref, err
+	}
+
+	switch ref.Granularity {
+	case GRANULARITY_BOOK:
+		ref.StartChapter = 1
+		ref.StartVerse = 1
+		ref.EndChapter = v.ChapterCount(ref.Book)
+		ref.EndVerse = v.VerseCount(ref.Book, ref.EndChapter)
+	case GRANULARITY_CHAPTER:
+		ref.StartVerse = 1
+		ref.EndVerse = v.VerseCount(ref.Book, ref.EndChapter)
+	}
+	return ref, nil
+}
+
+func plural(n uint) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
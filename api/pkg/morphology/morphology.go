@@ -0,0 +1,289 @@
+/**
+ * Package morphology decodes the parsing codes tagging the words of Greek
+ * and Hebrew texts: Robinson's codes for the Greek New Testament
+ * ("V-AAI-3S") and the Open Scriptures Hebrew Bible codes for the Hebrew and
+ * Aramaic Old Testament ("HVqp3ms").
+ */
+package morphology
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PartOfSpeech is the word class of a word or segment.
+type PartOfSpeech byte
+
+const (
+	POS_NONE PartOfSpeech = iota
+	POS_NOUN
+	POS_ADJECTIVE
+	POS_ARTICLE
+	POS_PRONOUN
+	POS_VERB
+	POS_ADVERB
+	POS_CONJUNCTION
+	POS_PREPOSITION
+	POS_PARTICLE
+	POS_INTERJECTION
+	// A pronominal or paragogic suffix (Hebrew)
+	POS_SUFFIX
+	// A transliterated Hebrew or Aramaic word (Greek)
+	POS_FOREIGN
+)
+
+var posNames = []string{"", "noun", "adjective", "article", "pronoun", "verb", "adverb",
+	"conjunction", "preposition", "particle", "interjection", "suffix", "foreign word"}
+
+type Tense byte
+
+const (
+	TENSE_NONE Tense = iota
+	TENSE_PRESENT
+	TENSE_IMPERFECT
+	TENSE_FUTURE
+	TENSE_AORIST
+	TENSE_PERFECT
+	TENSE_PLUPERFECT
+	TENSE_SECOND_FUTURE
+	TENSE_SECOND_AORIST
+	TENSE_SECOND_PERFECT
+	TENSE_SECOND_PLUPERFECT
+	// Robinson's "X", used for imperatival participles and infinitives
+	TENSE_NOT_STATED
+	// Weqatal (Hebrew)
+	TENSE_SEQUENTIAL_PERFECT
+	// Wayyiqtol (Hebrew)
+	TENSE_SEQUENTIAL_IMPERFECT
+)
+
+var tenseNames = []string{"", "present", "imperfect", "future", "aorist", "perfect", "pluperfect",
+	"second future", "second aorist", "second perfect", "second pluperfect", "no tense stated",
+	"sequential perfect", "sequential imperfect"}
+
+type Voice byte
+
+const (
+	VOICE_NONE Voice = iota
+	VOICE_ACTIVE
+	VOICE_MIDDLE
+	VOICE_PASSIVE
+	VOICE_MIDDLE_OR_PASSIVE
+	VOICE_MIDDLE_DEPONENT
+	VOICE_PASSIVE_DEPONENT
+	VOICE_MIDDLE_OR_PASSIVE_DEPONENT
+	VOICE_IMPERSONAL_ACTIVE
+	VOICE_NOT_STATED
+)
+
+var voiceNames = []string{"", "active", "middle", "passive", "middle or passive", "middle deponent",
+	"passive deponent", "middle or passive deponent", "impersonal active", "no voice stated"}
+
+type Mood byte
+
+const (
+	MOOD_NONE Mood = iota
+	MOOD_INDICATIVE
+	MOOD_SUBJUNCTIVE
+	MOOD_OPTATIVE
+	MOOD_IMPERATIVE
+	MOOD_INFINITIVE
+	MOOD_PARTICIPLE
+	// A participle used as an imperative (Greek)
+	MOOD_IMPERATIVE_PARTICIPLE
+	MOOD_COHORTATIVE
+	MOOD_JUSSIVE
+)
+
+var moodNames = []string{"", "indicative", "subjunctive", "optative", "imperative", "infinitive",
+	"participle", "imperative participle", "cohortative", "jussive"}
+
+type Person byte
+
+const (
+	PERSON_NONE Person = iota
+	PERSON_FIRST
+	PERSON_SECOND
+	PERSON_THIRD
+)
+
+var personNames = []string{"", "first person", "second person", "third person"}
+
+type Number byte
+
+const (
+	NUMBER_NONE Number = iota
+	NUMBER_SINGULAR
+	NUMBER_PLURAL
+	NUMBER_DUAL
+)
+
+var numberNames = []string{"", "singular", "plural", "dual"}
+
+type Gender byte
+
+const (
+	GENDER_NONE Gender = iota
+	GENDER_MASCULINE
+	GENDER_FEMININE
+	GENDER_NEUTER
+	// Either masculine or feminine (Hebrew "c")
+	GENDER_COMMON
+	// Masculine and feminine alike (Hebrew "b")
+	GENDER_BOTH
+)
+
+var genderNames = []string{"", "masculine", "feminine", "neuter", "common", "both"}
+
+type Case byte
+
+const (
+	CASE_NONE Case = iota
+	CASE_NOMINATIVE
+	CASE_GENITIVE
+	CASE_DATIVE
+	CASE_ACCUSATIVE
+	CASE_VOCATIVE
+)
+
+var caseNames = []string{"", "nominative", "genitive", "dative", "accusative", "vocative"}
+
+type State byte
+
+const (
+	STATE_NONE State = iota
+	STATE_ABSOLUTE
+	STATE_CONSTRUCT
+	// Aramaic emphatic state
+	STATE_DETERMINED
+)
+
+var stateNames = []string{"", "absolute", "construct", "determined"}
+
+// name returns the name of an enum value, "unknown" past the known ones.
+func name[T ~byte](names []string, value T) string {
+	if int(value) < len(names) {
+		return names[value]
+	}
+	return "unknown"
+}
+
+func (p PartOfSpeech) String() string { return name(posNames, p) }
+func (t Tense) String() string        { return name(tenseNames, t) }
+func (v Voice) String() string        { return name(voiceNames, v) }
+func (m Mood) String() string         { return name(moodNames, m) }
+func (p Person) String() string       { return name(personNames, p) }
+func (n Number) String() string       { return name(numberNames, n) }
+func (g Gender) String() string       { return name(genderNames, g) }
+func (c Case) String() string         { return name(caseNames, c) }
+func (s State) String() string        { return name(stateNames, s) }
+
+func (p PartOfSpeech) MarshalText() ([]byte, error) { return []byte(p.String()), nil }
+func (t Tense) MarshalText() ([]byte, error)        { return []byte(t.String()), nil }
+func (v Voice) MarshalText() ([]byte, error)        { return []byte(v.String()), nil }
+func (m Mood) MarshalText() ([]byte, error)         { return []byte(m.String()), nil }
+func (p Person) MarshalText() ([]byte, error)       { return []byte(p.String()), nil }
+func (n Number) MarshalText() ([]byte, error)       { return []byte(n.String()), nil }
+func (g Gender) MarshalText() ([]byte, error)       { return []byte(g.String()), nil }
+func (c Case) MarshalText() ([]byte, error)         { return []byte(c.String()), nil }
+func (s State) MarshalText() ([]byte, error)        { return []byte(s.String()), nil }
+
+/**
+ * Parsing is the grammar of a word, or of a segment of a Hebrew word (its
+ * prefixed conjunction, its pronominal suffix). Fields that do not apply are
+ * left at their zero value.
+ */
+type Parsing struct {
+	PartOfSpeech PartOfSpeech `json:"part_of_speech"`
+	// What kind of noun, pronoun or particle, or the degree of an adjective
+	// or adverb: "proper", "relative", "comparative", "direct object marker"
+	Type   string `json:"type,omitempty"`
+	Stem   string `json:"stem,omitempty"` // Hebrew and Aramaic verbs, e.g. qal
+	Tense  Tense  `json:"tense,omitempty"`
+	Voice  Voice  `json:"voice,omitempty"`
+	Mood   Mood   `json:"mood,omitempty"`
+	Person Person `json:"person,omitempty"`
+	Case   Case   `json:"case,omitempty"`
+	Gender Gender `json:"gender,omitempty"`
+	Number Number `json:"number,omitempty"`
+	State  State  `json:"state,omitempty"`
+	// The number of the possessor, for Greek possessive pronouns
+	PossessorNumber Number `json:"possessor_number,omitempty"`
+}
+
+// Label describes the parsing, e.g. "verb, qal, perfect, third person,
+// masculine, singular".
+func (p Parsing) Label() string {
+	var parts []string
+	for _, part := range []string{
+		p.PartOfSpeech.String(), p.Type, p.Stem, p.Tense.String(), p.Voice.String(), p.Mood.String(),
+		p.Person.String(), p.Case.String(), p.Gender.String(), p.Number.String(), p.State.String(),
+	} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if p.PossessorNumber != NUMBER_NONE {
+		parts = append(parts, p.PossessorNumber.String()+" possessor")
+	}
+	return strings.Join(parts, ", ")
+}
+
+/**
+ * Morphology is a decoded code. A Greek word is a single segment; a Hebrew
+ * word may be several, e.g. "HC/Vqw3ms" is a conjunction prefixed to a verb.
+ */
+type Morphology struct {
+	Language string    `json:"language"` // hbo, arc or grc
+	Code     string    `json:"code"`
+	Segments []Parsing `json:"segments"`
+}
+
+// Label describes every segment, separated by " | " as the BSB tables do.
+func (m Morphology) Label() string {
+	labels := make([]string, len(m.Segments))
+	for i, segment := range m.Segments {
+		labels[i] = segment.Label()
+	}
+	return strings.Join(labels, " | ")
+}
+
+// CodeError describes why a code could not be decoded.
+type CodeError struct {
+	Code string
+	Msg  string
+}
+
+func (e *CodeError) Error() string {
+	return fmt.Sprintf("%s in morphology code %q", e.Msg, e.Code)
+}
+
+/**
+ * Decode decodes a Robinson or OSHB code, as told by its OSIS prefix
+ * ("robinson:", "oshm:") or else by its shape: OSHB codes start with the
+ * language, H or A, and never hold a dash.
+ */
+func Decode(code string) (Morphology, error) {
+	code = strings.TrimSpace(code)
+	if prefix, rest, found := strings.Cut(code, ":"); found {
+		switch strings.ToLower(prefix) {
+		case "robinson":
+			return DecodeRobinson(rest)
+		case "oshm", "oshb":
+			return DecodeOSHB(rest)
+		}
+		return Morphology{}, &CodeError{Code: code, Msg: fmt.Sprintf("unknown scheme %q", prefix)}
+	}
+	if len(code) > 1 && (code[0] == 'H' || code[0] == 'A') && !strings.Contains(code, "-") {
+		m, err := DecodeOSHB(code)
+		if err == nil {
+			return m, nil
+		}
+		// "ADV" or "HEB" are Robinson codes
+		if m, robinsonErr := DecodeRobinson(code); robinsonErr == nil {
+			return m, nil
+		}
+		return Morphology{}, err
+	}
+	return DecodeRobinson(code)
+}
@@ -0,0 +1,307 @@
+package morphology
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Languages of OSHB codes, by the letter starting them.
+var oshbLanguages = map[byte]string{'H': "hbo", 'A': "arc"}
+
+var oshbAdjectiveTypes = map[byte]string{
+	'a': "",
+	'c': "cardinal number",
+	'g': "gentilic",
+	'o': "ordinal number",
+}
+
+var oshbNounTypes = map[byte]string{'c': "common", 'g': "gentilic", 'p': "proper"}
+
+// What a proper noun names ("Npl"); m and f are the gender of a person.
+var oshbProperNames = map[byte]string{'l': "location", 't': "title"}
+
+var oshbPronounTypes = map[byte]string{
+	'd': "demonstrative",
+	'f': "indefinite",
+	'i': "interrogative",
+	'p': "personal",
+	'r': "relative",
+}
+
+var oshbSuffixTypes = map[byte]string{
+	'd': "directional he",
+	'h': "paragogic he",
+	'n': "paragogic nun",
+	'p': "pronominal",
+}
+
+var oshbParticleTypes = map[byte]string{
+	'a': "affirmation",
+	'd': "definite article",
+	'e': "exhortation",
+	'i': "interrogative",
+	'j': "interjection",
+	'm': "demonstrative",
+	'n': "negative",
+	'o': "direct object marker",
+	'r': "relative",
+}
+
+var oshbHebrewStems = map[byte]string{
+	'q': "qal",
+	'N': "niphal",
+	'p': "piel",
+	'P': "pual",
+	'h': "hiphil",
+	'H': "hophal",
+	't': "hithpael",
+	'o': "polel",
+	'O': "polal",
+	'r': "hithpolel",
+	'm': "poel",
+	'M': "poal",
+	'k': "palel",
+	'K': "pulal",
+	'Q': "qal passive",
+	'l': "pilpel",
+	'L': "polpal",
+	'f': "hithpalpel",
+	'D': "nithpael",
+	'j': "pealal",
+	'i': "pilel",
+	'u': "hothpaal",
+	'c': "tiphil",
+	'v': "hishtaphel",
+	'w': "nithpalel",
+	'y': "nithpoel",
+	'z': "hithpoel",
+}
+
+var oshbAramaicStems = map[byte]string{
+	'q': "peal",
+	'Q': "peil",
+	'u': "hithpeel",
+	'p': "pael",
+	'P': "ithpaal",
+	'M': "hithpaal",
+	'a': "aphel",
+	'h': "haphel",
+	's': "saphel",
+	'e': "shaphel",
+	'H': "hophal",
+	'i': "ithpeel",
+	't': "hishtaphel",
+	'v': "ishtaphal",
+	'w': "hithaphel",
+	'o': "polel",
+	'z': "ithpoel",
+	'r': "hithpolel",
+	'f': "hithpalpel",
+	'b': "hephal",
+	'c': "tiphel",
+	'm': "poel",
+	'l': "palpel",
+	'L': "ithpalpel",
+	'O': "ithpolel",
+	'G': "ittaphal",
+}
+
+/**
+ * Verb conjugations. Participles go on with gender, number and state,
+ * infinitives end there, and the other forms go on with person, gender and
+ * number.
+ */
+var oshbConjugations = map[byte]Parsing{
+	'p': {Tense: TENSE_PERFECT},
+	'q': {Tense: TENSE_SEQUENTIAL_PERFECT},
+	'i': {Tense: TENSE_IMPERFECT},
+	'w': {Tense: TENSE_SEQUENTIAL_IMPERFECT},
+	'h': {Mood: MOOD_COHORTATIVE},
+	'j': {Mood: MOOD_JUSSIVE},
+	'v': {Mood: MOOD_IMPERATIVE},
+	'r': {Mood: MOOD_PARTICIPLE, Voice: VOICE_ACTIVE},
+	's': {Mood: MOOD_PARTICIPLE, Voice: VOICE_PASSIVE},
+	'a': {Mood: MOOD_INFINITIVE, State: STATE_ABSOLUTE},
+	'c': {Mood: MOOD_INFINITIVE, State: STATE_CONSTRUCT},
+}
+
+var oshbPersons = map[byte]Person{'1': PERSON_FIRST, '2': PERSON_SECOND, '3': PERSON_THIRD}
+
+var oshbGenders = map[byte]Gender{
+	'm': GENDER_MASCULINE,
+	'f': GENDER_FEMININE,
+	'b': GENDER_BOTH,
+	'c': GENDER_COMMON,
+}
+
+var oshbNumbers = map[byte]Number{'s': NUMBER_SINGULAR, 'p': NUMBER_PLURAL, 'd': NUMBER_DUAL}
+
+var oshbStates = map[byte]State{'a': STATE_ABSOLUTE, 'c': STATE_CONSTRUCT, 'd': STATE_DETERMINED}
+
+/**
+ * DecodeOSHB decodes a morphology code of the Open Scriptures Hebrew Bible:
+ * the language (H or A) followed by the segments of the word separated by
+ * "/", e.g. "HC/Vqw3ms" (conjunction, then verb, qal, sequential imperfect,
+ * third person, masculine, singular) or "HNcmsc/Sp3ms". Fields a code leaves
+ * off at its end, or marks "x", are left empty.
+ */
+func DecodeOSHB(code string) (Morphology, error) {
+	fail := func(format string, args ...interface{}) (Morphology, error) {
+		return Morphology{}, &CodeError{Code: code, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	trimmed := strings.TrimSpace(code)
+	if trimmed == "" {
+		return fail("empty code")
+	}
+	language, ok := oshbLanguages[trimmed[0]]
+	if !ok {
+		return fail("unknown language %q", trimmed[0])
+	}
+	m := Morphology{Language: language, Code: code}
+	for _, segment := range strings.Split(trimmed[1:], "/") {
+		p, err := oshbSegment(segment, language == "arc")
+		if err != nil {
+			return fail("%s", err)
+		}
+		m.Segments = append(m.Segments, p)
+	}
+	return m, nil
+}
+
+// oshbSegment decodes a segment of an OSHB code, e.g. "Vqp3ms".
+func oshbSegment(segment string, aramaic bool) (Parsing, error) {
+	var p Parsing
+	if segment == "" {
+		return p, fmt.Errorf("empty segment")
+	}
+	s := segment[1:]
+	var err error
+	switch segment[0] {
+	case 'A':
+		p.PartOfSpeech = POS_ADJECTIVE
+		if p.Type, err = oshbField(&s, oshbAdjectiveTypes, "adjective type"); err == nil {
+			err = oshbGenderNumberState(&p, &s)
+		}
+	case 'C':
+		p.PartOfSpeech = POS_CONJUNCTION
+	case 'D':
+		p.PartOfSpeech = POS_ADVERB
+	case 'N':
+		p.PartOfSpeech = POS_NOUN
+		if p.Type, err = oshbField(&s, oshbNounTypes, "noun type"); err != nil {
+			break
+		}
+		if p.Type != "proper" {
+			err = oshbGenderNumberState(&p, &s)
+			break
+		}
+		var name string
+		if len(s) > 0 && (s[0] == 'm' || s[0] == 'f') {
+			p.Gender, err = oshbField(&s, oshbGenders, "gender")
+		} else if name, err = oshbField(&s, oshbProperNames, "proper noun type"); name != "" {
+			p.Type += ", " + name
+		}
+	case 'P':
+		p.PartOfSpeech = POS_PRONOUN
+		if p.Type, err = oshbField(&s, oshbPronounTypes, "pronoun type"); err == nil {
+			err = oshbPersonGenderNumber(&p, &s)
+		}
+	case 'R':
+		p.PartOfSpeech = POS_PREPOSITION
+		if strings.HasPrefix(s, "d") {
+			p.Type = "with definite article"
+			s = s[1:]
+		}
+	case 'S':
+		p.PartOfSpeech = POS_SUFFIX
+		if p.Type, err = oshbField(&s, oshbSuffixTypes, "suffix type"); err == nil && p.Type == "pronominal" {
+			err = oshbPersonGenderNumber(&p, &s)
+		}
+	case 'T':
+		p.PartOfSpeech = POS_PARTICLE
+		p.Type, err = oshbField(&s, oshbParticleTypes, "particle type")
+	case 'V':
+		p, err = oshbVerb(&s, aramaic)
+	default:
+		return p, fmt.Errorf("unknown part of speech %q", segment[0])
+	}
+	if err != nil {
+		return p, err
+	}
+	if s != "" {
+		return p, fmt.Errorf("unexpected %q after %q", s, segment[:len(segment)-len(s)])
+	}
+	return p, nil
+}
+
+// oshbVerb decodes the stem and conjugation of a verb and what follows them.
+func oshbVerb(s *string, aramaic bool) (Parsing, error) {
+	stems := oshbHebrewStems
+	if aramaic {
+		stems = oshbAramaicStems
+	}
+	if len(*s) < 2 {
+		return Parsing{}, fmt.Errorf("missing stem and conjugation")
+	}
+	stem, ok := stems[(*s)[0]]
+	if !ok {
+		return Parsing{}, fmt.Errorf("unknown stem %q", (*s)[0])
+	}
+	p, ok := oshbConjugations[(*s)[1]]
+	if !ok {
+		return Parsing{}, fmt.Errorf("unknown conjugation %q", (*s)[1])
+	}
+	p.PartOfSpeech = POS_VERB
+	p.Stem = stem
+	*s = (*s)[2:]
+
+	switch p.Mood {
+	case MOOD_INFINITIVE:
+		return p, nil
+	case MOOD_PARTICIPLE:
+		return p, oshbGenderNumberState(&p, s)
+	}
+	return p, oshbPersonGenderNumber(&p, s)
+}
+
+func oshbGenderNumberState(p *Parsing, s *string) (err error) {
+	if p.Gender, err = oshbField(s, oshbGenders, "gender"); err != nil {
+		return err
+	}
+	if p.Number, err = oshbField(s, oshbNumbers, "number"); err != nil {
+		return err
+	}
+	p.State, err = oshbField(s, oshbStates, "state")
+	return err
+}
+
+func oshbPersonGenderNumber(p *Parsing, s *string) (err error) {
+	if p.Person, err = oshbField(s, oshbPersons, "person"); err != nil {
+		return err
+	}
+	if p.Gender, err = oshbField(s, oshbGenders, "gender"); err != nil {
+		return err
+	}
+	p.Number, err = oshbField(s, oshbNumbers, "number")
+	return err
+}
+
+// oshbField decodes the next letter of s, which is left off past the end of
+// a code and "x" where the field does not apply.
+func oshbField[T any](s *string, values map[byte]T, field string) (T, error) {
+	var value T
+	if *s == "" {
+		return value, nil
+	}
+	letter := (*s)[0]
+	*s = (*s)[1:]
+	if letter == 'x' {
+		return value, nil
+	}
+	value, ok := values[letter]
+	if !ok {
+		return value, fmt.Errorf("unknown %s %q", field, letter)
+	}
+	return value, nil
+}
@@ -0,0 +1,258 @@
+package morphology
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Words without inflection, before any suffix ("ADV-C").
+var robinsonIndeclinables = map[string]Parsing{
+	"ADV":  {PartOfSpeech: POS_ADVERB},
+	"CONJ": {PartOfSpeech: POS_CONJUNCTION},
+	"COND": {PartOfSpeech: POS_CONJUNCTION, Type: "conditional"},
+	"PRT":  {PartOfSpeech: POS_PARTICLE},
+	"PREP": {PartOfSpeech: POS_PREPOSITION},
+	"INJ":  {PartOfSpeech: POS_INTERJECTION},
+	"ARAM": {PartOfSpeech: POS_FOREIGN, Type: "Aramaic"},
+	"HEB":  {PartOfSpeech: POS_FOREIGN, Type: "Hebrew"},
+}
+
+// Declined words, by the letter starting their code.
+var robinsonNominals = map[string]Parsing{
+	"N": {PartOfSpeech: POS_NOUN},
+	"A": {PartOfSpeech: POS_ADJECTIVE},
+	"T": {PartOfSpeech: POS_ARTICLE, Type: "definite"},
+	"P": {PartOfSpeech: POS_PRONOUN, Type: "personal"},
+	"R": {PartOfSpeech: POS_PRONOUN, Type: "relative"},
+	"C": {PartOfSpeech: POS_PRONOUN, Type: "reciprocal"},
+	"D": {PartOfSpeech: POS_PRONOUN, Type: "demonstrative"},
+	"K": {PartOfSpeech: POS_PRONOUN, Type: "correlative"},
+	"I": {PartOfSpeech: POS_PRONOUN, Type: "interrogative"},
+	"X": {PartOfSpeech: POS_PRONOUN, Type: "indefinite"},
+	"Q": {PartOfSpeech: POS_PRONOUN, Type: "correlative or interrogative"},
+	"F": {PartOfSpeech: POS_PRONOUN, Type: "reflexive"},
+	"S": {PartOfSpeech: POS_PRONOUN, Type: "possessive"},
+}
+
+// Suffixes qualifying a word, e.g. "A-NSM-C", "ADV-I", "N-PRI".
+var robinsonSuffixes = map[string]string{
+	"C":   "comparative",
+	"S":   "superlative",
+	"I":   "interrogative",
+	"N":   "negative",
+	"K":   "crasis",
+	"ATT": "Attic form",
+	"ABB": "abbreviated",
+	"P":   "with attached particle",
+	"L":   "location",
+	"T":   "title",
+	"PRI": "proper indeclinable",
+	"NUI": "numeral indeclinable",
+	"LI":  "letter indeclinable",
+	"OI":  "other indeclinable",
+}
+
+var robinsonTenses = map[byte]Tense{
+	'P': TENSE_PRESENT,
+	'I': TENSE_IMPERFECT,
+	'F': TENSE_FUTURE,
+	'A': TENSE_AORIST,
+	'R': TENSE_PERFECT,
+	'L': TENSE_PLUPERFECT,
+	'X': TENSE_NOT_STATED,
+}
+
+// Tenses of Robinson's "2" forms, e.g. "V-2AAI-3S".
+var robinsonSecondTenses = map[Tense]Tense{
+	TENSE_FUTURE:     TENSE_SECOND_FUTURE,
+	TENSE_AORIST:     TENSE_SECOND_AORIST,
+	TENSE_PERFECT:    TENSE_SECOND_PERFECT,
+	TENSE_PLUPERFECT: TENSE_SECOND_PLUPERFECT,
+}
+
+var robinsonVoices = map[byte]Voice{
+	'A': VOICE_ACTIVE,
+	'M': VOICE_MIDDLE,
+	'P': VOICE_PASSIVE,
+	'E': VOICE_MIDDLE_OR_PASSIVE,
+	'D': VOICE_MIDDLE_DEPONENT,
+	'O': VOICE_PASSIVE_DEPONENT,
+	'N': VOICE_MIDDLE_OR_PASSIVE_DEPONENT,
+	'Q': VOICE_IMPERSONAL_ACTIVE,
+	'X': VOICE_NOT_STATED,
+}
+
+var robinsonMoods = map[byte]Mood{
+	'I': MOOD_INDICATIVE,
+	'S': MOOD_SUBJUNCTIVE,
+	'O': MOOD_OPTATIVE,
+	'M': MOOD_IMPERATIVE,
+	'N': MOOD_INFINITIVE,
+	'P': MOOD_PARTICIPLE,
+	'R': MOOD_IMPERATIVE_PARTICIPLE,
+}
+
+var robinsonPersons = map[byte]Person{'1': PERSON_FIRST, '2': PERSON_SECOND, '3': PERSON_THIRD}
+
+var robinsonCases = map[byte]Case{
+	'N': CASE_NOMINATIVE,
+	'G': CASE_GENITIVE,
+	'D': CASE_DATIVE,
+	'A': CASE_ACCUSATIVE,
+	'V': CASE_VOCATIVE,
+}
+
+var robinsonNumbers = map[byte]Number{'S': NUMBER_SINGULAR, 'P': NUMBER_PLURAL}
+
+var robinsonGenders = map[byte]Gender{'M': GENDER_MASCULINE, 'F': GENDER_FEMININE, 'N': GENDER_NEUTER}
+
+/**
+ * DecodeRobinson decodes a Robinson morphology code of the Greek New
+ * Testament: "V-AAI-3S" (verb, aorist active indicative, third person
+ * singular), "N-GSF", "P-1DP", "S-1SNSF", "ADV-I", "A-NSM-C". Codes are
+ * read case-insensitively.
+ */
+func DecodeRobinson(code string) (Morphology, error) {
+	m := Morphology{Language: "grc", Code: code}
+	fail := func(format string, args ...interface{}) (Morphology, error) {
+		return Morphology{}, &CodeError{Code: code, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	parts := strings.Split(strings.ToUpper(strings.TrimSpace(code)), "-")
+	head, rest := parts[0], parts[1:]
+	var p Parsing
+	switch base, nominal := robinsonNominals[head]; {
+	case head == "V":
+		if len(rest) == 0 {
+			return fail("missing tense, voice and mood")
+		}
+		var err error
+		if p, rest, err = robinsonVerb(rest); err != nil {
+			return fail("%s", err)
+		}
+	case nominal:
+		p = base
+		if len(rest) > 0 {
+			if _, suffix := robinsonSuffixes[rest[0]]; !suffix || head == "S" && len(rest[0]) > 1 {
+				if err := robinsonInflection(&p, head, rest[0]); err != nil {
+					return fail("%s", err)
+				}
+				rest = rest[1:]
+			}
+		}
+	default:
+		indeclinable, ok := robinsonIndeclinables[head]
+		if !ok {
+			return fail("unknown part of speech %q", head)
+		}
+		p = indeclinable
+	}
+
+	for _, suffix := range rest {
+		qualifier, ok := robinsonSuffixes[suffix]
+		if !ok {
+			return fail("unknown suffix %q", suffix)
+		}
+		if p.Type != "" {
+			qualifier = p.Type + ", " + qualifier
+		}
+		p.Type = qualifier
+	}
+	m.Segments = []Parsing{p}
+	return m, nil
+}
+
+// robinsonVerb decodes the tense, voice and mood of a verb and what follows
+// them, returning the parts left.
+func robinsonVerb(parts []string) (Parsing, []string, error) {
+	p := Parsing{PartOfSpeech: POS_VERB}
+	tvm := parts[0]
+	second := strings.HasPrefix(tvm, "2")
+	tvm = strings.TrimPrefix(tvm, "2")
+	if len(tvm) != 3 {
+		return p, nil, fmt.Errorf("invalid tense, voice and mood %q", parts[0])
+	}
+	var ok bool
+	if p.Tense, ok = robinsonTenses[tvm[0]]; !ok {
+		return p, nil, fmt.Errorf("unknown tense %q", tvm[0])
+	}
+	if second {
+		if p.Tense, ok = robinsonSecondTenses[p.Tense]; !ok {
+			return p, nil, fmt.Errorf("no second form of the %s", robinsonTenses[tvm[0]])
+		}
+	}
+	if p.Voice, ok = robinsonVoices[tvm[1]]; !ok {
+		return p, nil, fmt.Errorf("unknown voice %q", tvm[1])
+	}
+	if p.Mood, ok = robinsonMoods[tvm[2]]; !ok {
+		return p, nil, fmt.Errorf("unknown mood %q", tvm[2])
+	}
+	parts = parts[1:]
+
+	switch p.Mood {
+	case MOOD_INFINITIVE:
+		return p, parts, nil
+	case MOOD_PARTICIPLE, MOOD_IMPERATIVE_PARTICIPLE:
+		if len(parts) == 0 {
+			return p, nil, fmt.Errorf("missing case, number and gender")
+		}
+		err := robinsonInflection(&p, "V", parts[0])
+		return p, parts[1:], err
+	}
+	// Finite verbs: person and number
+	if len(parts) == 0 || len(parts[0]) != 2 {
+		return p, nil, fmt.Errorf("missing person and number")
+	}
+	if p.Person, ok = robinsonPersons[parts[0][0]]; !ok {
+		return p, nil, fmt.Errorf("unknown person %q", parts[0][0])
+	}
+	if p.Number, ok = robinsonNumbers[parts[0][1]]; !ok {
+		return p, nil, fmt.Errorf("unknown number %q", parts[0][1])
+	}
+	return p, parts[1:], nil
+}
+
+/**
+ * robinsonInflection decodes the inflection of a declined word: case,
+ * number and gender ("NSM"), after the person for personal, reflexive and
+ * possessive pronouns ("1DP", "3ASM"), and the number of the possessor for
+ * possessive ones ("1SNSF"). Personal pronouns of the first and second
+ * person have no gender.
+ */
+func robinsonInflection(p *Parsing, head string, inflection string) error {
+	s := inflection
+	var ok bool
+	if len(s) > 0 && s[0] >= '0' && s[0] <= '9' {
+		if head != "P" && head != "F" && head != "S" {
+			return fmt.Errorf("unexpected person in %q", inflection)
+		}
+		if p.Person, ok = robinsonPersons[s[0]]; !ok {
+			return fmt.Errorf("unknown person %q", s[0])
+		}
+		s = s[1:]
+		if head == "S" {
+			if len(s) == 0 {
+				return fmt.Errorf("missing possessor number in %q", inflection)
+			}
+			if p.PossessorNumber, ok = robinsonNumbers[s[0]]; !ok {
+				return fmt.Errorf("unknown number %q", s[0])
+			}
+			s = s[1:]
+		}
+	}
+	if len(s) < 2 || len(s) > 3 {
+		return fmt.Errorf("invalid case, number and gender %q", inflection)
+	}
+	if p.Case, ok = robinsonCases[s[0]]; !ok {
+		return fmt.Errorf("unknown case %q", s[0])
+	}
+	if p.Number, ok = robinsonNumbers[s[1]]; !ok {
+		return fmt.Errorf("unknown number %q", s[1])
+	}
+	if len(s) == 3 {
+		if p.Gender, ok = robinsonGenders[s[2]]; !ok {
+			return fmt.Errorf("unknown gender %q", s[2])
+		}
+	}
+	return nil
+}
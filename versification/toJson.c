@@ -1,4 +1,5 @@
 #include <stdio.h>
+#include <string.h>
 #include <sbook.h>
 
 #include <canon.h>
@@ -31,9 +32,12 @@
  *      getSystemVersificationMgr()
  *      loadFromSBook();
  *
- *  :NOTE: Currently we don't process `versification.mappings`
+ *  Run with `--mappings` to instead convert `versification.mappings`, see
+ *  printMappings().
  */
-int main( int argc, char* argv ) {
+void printMappings( struct versification* vv, int vv_cnt );
+
+int main( int argc, char** argv ) {
   struct versification vv[] = {
     // name           sbooks[]              sbooks[]          int[]       unsigned char[]
     {"KJV",          otbooks,              ntbooks,          vm},
@@ -58,6 +62,11 @@ int main( int argc, char* argv ) {
   int vv_cnt  = sizeof(vv) / sizeof( vv[0] );
   int idex;
 
+  if (argc > 1 && strcmp( argv[1], "--mappings" ) == 0) {
+    printMappings( vv, vv_cnt );
+    return 0;
+  }
+
   printf("{\n");
   for (idex = 0; idex < vv_cnt; idex++) {
     struct versification  v_cur  = vv[idex];
@@ -94,6 +103,9 @@ int main( int argc, char* argv ) {
       }
 
       printf(" ]");
+
+      // `vm` is one flat list for all books; step past this book's chapters
+      vm += bk->chapmax;
       bk++;
     }
 
@@ -118,6 +130,8 @@ int main( int argc, char* argv ) {
       }
 
       printf(" ]");
+
+      vm += bk->chapmax;
       bk++;
     }
     // New Testament }
@@ -126,3 +140,98 @@ int main( int argc, char* argv ) {
   }
   printf("\n}\n");
 }
+
+/**
+ *  Return the OSIS name of the 1-based book number `num` within `v`
+ *  (Old Testament books followed by New Testament books).
+ */
+const char* bookOsis( struct versification* v, int num ) {
+  struct sbook* bk;
+  int           cnt = 0;
+
+  for (bk = v->ot; bk->chapmax > 0; bk++) {
+    if (++cnt == num) { return bk->osis; }
+  }
+  for (bk = v->nt; bk->chapmax > 0; bk++) {
+    if (++cnt == num) { return bk->osis; }
+  }
+  return NULL;
+}
+
+/**
+ *  Convert Sword versification mappings into a JSON object of the form:
+ *    { "%SCHEME%": [
+ *        [ "%Book%", ch, vs, vsEnd, "%KJVA Book%", ch, vs, vsEnd ],
+ *        ...
+ *    ], ... }
+ *
+ *  Each entry maps a verse (or verse range when vsEnd > 0) of the scheme to
+ *  the KJVA scheme. Verses following an entry within the same chapter are
+ *  shifted by the same amount up to the next entry. Schemes without mappings
+ *  are identical to KJVA.
+ *
+ *  Based off the way Sword loads mappings:
+ *    sword/src/mgr/versificationmgr.cpp
+ *      loadFromSBook();
+ *
+ *  The mappings data starts with the names of any extra books (books not
+ *  present within the scheme itself), each NUL terminated, followed by an
+ *  empty name. Next are 7 byte entries:
+ *    book, chapter, verse, verseEnd, kjvaChapter, kjvaVerse, kjvaVerseEnd
+ *  When `book` is beyond the books of the scheme it identifies an extra book
+ *  as the target and an 8th byte holds the source book.
+ */
+void printMappings( struct versification* vv, int vv_cnt ) {
+  int idex;
+  int is_first_scheme = 1;
+
+  printf("{\n");
+  for (idex = 0; idex < vv_cnt; idex++) {
+    struct versification* v_cur     = &vv[idex];
+    const unsigned char*  m         = v_cur->mappings;
+    const char*           extras[16];
+    int                   extra_cnt = 0;
+    int                   book_cnt  = 0;
+    int                   is_first  = 1;
+    struct sbook*         bk;
+
+    if (m == NULL) { continue; }
+
+    for (bk = v_cur->ot; bk->chapmax > 0; bk++) { book_cnt++; }
+    for (bk = v_cur->nt; bk->chapmax > 0; bk++) { book_cnt++; }
+
+    // Extra book names
+    while (*m) {
+      extras[extra_cnt++] = (const char*)m;
+      m += strlen( (const char*)m ) + 1;
+    }
+    m++;
+
+    if (is_first_scheme)  { is_first_scheme = 0; }
+    else                  { printf(",\n"); }
+
+    printf("  \"%s\": [", v_cur->name);
+
+    for (; *m; m += 7) {
+      const char* src = bookOsis( v_cur, m[0] );
+      const char* dst = src;
+
+      if (m[0] > book_cnt) {
+        // Target is an extra book, the source book follows the entry
+        dst = extras[ m[0] - book_cnt - 1 ];
+        src = bookOsis( v_cur, m[7] );
+      }
+
+      if (is_first) { is_first = 0; }
+      else          { printf(","); }
+
+      printf("\n    [ \"%s\", %3d, %3d, %3d, \"%s\", %3d, %3d, %3d ]",
+              src, m[1], m[2], m[3], dst, m[4], m[5], m[6]);
+
+      if (m[0] > book_cnt) { m++; }
+    }
+
+    printf("\n  ]");
+  }
+  printf("\n}\n");
+}
@@ -416,6 +416,9 @@ function _process_row( row, state ) {
   }
 
   const entry = {
+    heb_sort,
+    grk_sort,
+    bsb_sort,
     language,
     abs_vs,
     wlc,